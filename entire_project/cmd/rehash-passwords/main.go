@@ -0,0 +1,49 @@
+// Package main 一次性密码迁移工具
+// 学习要点：把历史遗留的明文密码批量转换为 bcrypt 哈希，可重复执行
+//
+// 用法：
+//
+//	go run ./cmd/rehash-passwords
+//	CONFIG_PATH=configs/config.yaml go run ./cmd/rehash-passwords
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"task-management-system/internal/config"
+	"task-management-system/internal/database"
+	"task-management-system/internal/services"
+	"task-management-system/pkg/cache"
+)
+
+func main() {
+	// 加载配置
+	configPath := "configs/config.yaml"
+	if envConfigPath := os.Getenv("CONFIG_PATH"); envConfigPath != "" {
+		configPath = envConfigPath
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		log.Fatalf("配置初始化失败: %v", err)
+	}
+
+	// 连接数据库
+	db, err := database.Open(&cfg.Database.MySQL)
+	if err != nil {
+		log.Fatalf("数据库初始化失败: %v", err)
+	}
+	defer database.Close(db)
+
+	// 迁移只涉及数据库，使用进程内缓存即可，不需要连接Redis
+	userService := services.NewUserService(db, cache.NewMemoryCache(0), nil, nil)
+
+	count, err := userService.RehashPlaintextPasswords(context.Background())
+	if err != nil {
+		log.Fatalf("密码迁移失败（已处理 %d 个用户）: %v", count, err)
+	}
+
+	fmt.Printf("✅ 密码迁移完成，共重新加密 %d 个用户的密码\n", count)
+}
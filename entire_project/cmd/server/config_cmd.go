@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// configUsage 配置子命令用法
+const configUsage = `用法: server config <命令>
+
+命令:
+  dump      输出合并后的最终配置（密钥已脱敏）
+  validate  只加载并校验配置
+
+配置文件由 CONFIG_PATH 指定（默认 configs/config.yaml），
+环境配置文件由 TASK_PROFILE 指定，环境变量如 TASK_DATABASE_MYSQL_PASSWORD 覆盖对应字段`
+
+// runConfig 执行配置子命令
+// 学习要点：部署前用 config dump 确认文件、环境变量和密钥文件合并后的实际结果
+func runConfig(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("缺少配置命令\n%s", configUsage)
+	}
+
+	cfg, err := initConfig()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "dump":
+		return cfg.Dump(os.Stdout)
+	case "validate":
+		fmt.Println("配置校验通过")
+		return nil
+	default:
+		return fmt.Errorf("未知的配置命令: %s\n%s", args[0], configUsage)
+	}
+}
@@ -3,18 +3,25 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime"
 	"syscall"
 	"time"
 
+	"gorm.io/gorm"
+	"task-management-system/internal/app"
 	"task-management-system/internal/config"
 	"task-management-system/internal/database"
 	"task-management-system/internal/handlers"
-	"task-management-system/pkg/redis"
+	"task-management-system/pkg/cache"
+	"task-management-system/pkg/logger"
+	"task-management-system/pkg/tracing"
 )
 
 // @title 任务管理系统API
@@ -33,123 +40,254 @@ import (
 // @BasePath /api/v1
 
 func main() {
+	// 子命令：server migrate up|down|status|to N|baseline N
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			fatal("数据库迁移失败", err)
+		}
+		return
+	}
+	
+	// 子命令：server config dump|validate
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		if err := runConfig(os.Args[2:]); err != nil {
+			fatal("配置命令执行失败", err)
+		}
+		return
+	}
+	
 	// 打印启动横幅
 	printBanner()
 	
 	// 1. 加载配置
 	// 学习要点：配置文件的加载顺序，环境变量的优先级
-	if err := initConfig(); err != nil {
-		log.Fatalf("配置初始化失败: %v", err)
+	cfg, err := initConfig()
+	if err != nil {
+		fatal("配置初始化失败", err)
+	}
+	
+	// 初始化日志
+	// 学习要点：日志依赖配置，必须在配置加载之后、其他组件之前初始化
+	if err := logger.Init(&cfg.Log); err != nil {
+		fatal("日志初始化失败", err)
+	}
+	slog.Info("配置加载完成")
+	
+	// 初始化链路追踪
+	// 学习要点：必须在创建数据库和缓存客户端之前完成，埋点才能拿到真正的 TracerProvider
+	shutdownTracing, err := tracing.Init(context.Background(), &cfg.Tracing)
+	if err != nil {
+		fatal("链路追踪初始化失败", err)
 	}
-	fmt.Println("✅ 配置加载完成")
 	
 	// 2. 初始化数据库连接
 	// 学习要点：数据库连接的初始化，连接池配置
-	if err := initDatabase(); err != nil {
-		log.Fatalf("数据库初始化失败: %v", err)
+	db, err := initDatabase(cfg)
+	if err != nil {
+		fatal("数据库初始化失败", err)
 	}
-	fmt.Println("✅ 数据库初始化完成")
+	slog.Info("数据库初始化完成")
 	
-	// 3. 初始化Redis连接
-	// 学习要点：Redis连接初始化，缓存系统集成
-	if err := initRedis(); err != nil {
-		log.Fatalf("Redis初始化失败: %v", err)
+	// 3. 初始化缓存
+	// 学习要点：根据配置选择 Redis 或进程内缓存
+	cacheStore, err := initCache(cfg)
+	if err != nil {
+		fatal("缓存初始化失败", err)
 	}
-	fmt.Println("✅ Redis初始化完成")
+	slog.Info("缓存初始化完成", "driver", cfg.Redis.GetDriver())
 	
-	// 4. 设置路由
-	// 学习要点：HTTP路由的设置，中间件的应用
-	router := handlers.SetupRoutes()
-	fmt.Println("✅ 路由设置完成")
+	// 4. 创建应用容器并设置路由
+	// 学习要点：依赖在这里集中创建一次，再注入到服务和处理器中
+	container := app.NewContainer(cfg, db, cacheStore)
+	container.BuildInfo = GetBuildInfo()
+	router := handlers.SetupRoutes(container)
+	slog.Info("路由设置完成")
+	
+	// 监听配置文件，日志级别和缓存有效期修改后无需重启
+	// 监听失败不影响服务运行，只是配置修改需要重启后生效
+	stopWatch, err := config.Watch(configPath(), func(next *config.Config) {
+		applyConfig(container, next)
+	})
+	if err != nil {
+		slog.Warn("监听配置文件失败，配置修改需要重启后生效", "error", err)
+		stopWatch = func() {}
+	}
 	
 	// 5. 启动HTTP服务器
-	// 学习要点：HTTP服务器的启动，端口配置
-	serverAddr := fmt.Sprintf(":%d", config.GlobalConfig.Server.Port)
-	fmt.Printf("🚀 服务器启动成功，监听端口: %s\n", serverAddr)
-	fmt.Printf("📖 API文档地址: http://localhost%s/swagger/index.html\n", serverAddr)
-	fmt.Printf("🔍 健康检查: http://localhost%s/health\n", serverAddr)
+	// 学习要点：使用 http.Server 而不是 http.ListenAndServe，才能设置超时并调用 Shutdown
+	srv := newHTTPServer(&cfg.Server, router)
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("服务器启动成功", "addr", srv.Addr,
+			"swagger", fmt.Sprintf("http://localhost%s/swagger/index.html", srv.Addr),
+			"health", fmt.Sprintf("http://localhost%s/health", srv.Addr))
+		// Shutdown 之后 ListenAndServe 立即返回 http.ErrServerClosed，这不是错误
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
 	
-	// 6. 优雅关闭处理
+	// 6. 等待关闭信号或服务器异常退出
 	// 学习要点：信号处理，资源清理，优雅关闭
-	go handleGracefulShutdown()
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	
+	exitCode := 0
+	select {
+	case sig := <-quit:
+		slog.Info("收到关闭信号，开始优雅关闭", "signal", sig.String())
+	case err := <-serverErr:
+		slog.Error("服务器启动失败", "error", err)
+		exitCode = 1
+	}
 	
-	// 启动HTTP服务器
-	if err := http.ListenAndServe(serverAddr, router); err != nil {
-		log.Fatalf("服务器启动失败: %v", err)
+	if err := gracefulShutdown(srv, container, stopWatch, shutdownTracing); err != nil {
+		exitCode = 1
 	}
+	os.Exit(exitCode)
 }
 
-// initConfig 初始化配置
-func initConfig() error {
-	// 默认配置文件路径
-	configPath := "configs/config.yaml"
-	
-	// 检查环境变量中是否指定了配置文件路径
+// newHTTPServer 创建HTTP服务器
+// 学习要点：读写超时防止慢客户端长期占用连接；写超时要大于请求处理超时，否则504响应无法写出
+func newHTTPServer(cfg *config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.GetReadTimeout(),
+		ReadTimeout:       cfg.GetReadTimeout(),
+		WriteTimeout:      cfg.GetWriteTimeout(),
+		IdleTimeout:       cfg.GetIdleTimeout(),
+	}
+}
+
+// configPath 获取配置文件路径，可通过环境变量 CONFIG_PATH 指定
+func configPath() string {
 	if envConfigPath := os.Getenv("CONFIG_PATH"); envConfigPath != "" {
-		configPath = envConfigPath
+		return envConfigPath
+	}
+	return "configs/config.yaml"
+}
+
+// initConfig 初始化配置
+// 学习要点：环境配置文件由 TASK_PROFILE 指定，如 TASK_PROFILE=prod 时合并 configs/config.prod.yaml
+func initConfig() (*config.Config, error) {
+	return config.Load(configPath())
+}
+
+// applyConfig 应用热更新的配置
+// 学习要点：只有日志级别和缓存有效期可以热更新，其他修改只提示需要重启
+func applyConfig(container *app.Container, next *config.Config) {
+	if err := logger.SetLevel(next.Log.Level); err != nil {
+		slog.Error("更新日志级别失败", "error", err)
+		return
 	}
+	container.Live.Store(next)
+	slog.Info("配置已热更新", "log_level", next.Log.Level)
 	
-	return config.Load(configPath)
+	if config.RequiresRestart(container.Config, next) {
+		slog.Warn("配置文件中除日志级别和缓存有效期以外的修改需要重启后生效")
+	}
 }
 
 // initDatabase 初始化数据库
-func initDatabase() error {
+func initDatabase(cfg *config.Config) (*gorm.DB, error) {
 	// 初始化MySQL连接
-	if err := database.InitMySQL(); err != nil {
-		return err
+	db, err := database.Open(&cfg.Database.MySQL)
+	if err != nil {
+		return nil, err
 	}
 	
-	// 自动迁移数据库表结构
-	if err := database.AutoMigrate(); err != nil {
-		return err
+	// 同步表结构
+	// 学习要点：开发模式直接用 AutoMigrate 跟随模型变化；其他环境执行版本化迁移，
+	// 多个副本同时启动时由迁移锁保证只有一个实例在执行
+	if cfg.Server.IsDevMode() {
+		if err := database.AutoMigrate(db); err != nil {
+			return nil, err
+		}
+	} else {
+		migrator, err := database.NewMigrator(db)
+		if err != nil {
+			return nil, err
+		}
+		if err := migrator.Up(context.Background()); err != nil {
+			return nil, err
+		}
 	}
 	
 	// 初始化种子数据
-	if err := database.SeedData(); err != nil {
-		return err
+	// 注意：种子数据包含默认账号和密码，只在开发模式创建
+	if cfg.Server.IsDevMode() {
+		if err := database.SeedData(db); err != nil {
+			return nil, err
+		}
 	}
 	
-	return nil
+	return db, nil
 }
 
-// initRedis 初始化Redis
-func initRedis() error {
-	return redis.InitRedis()
+// initCache 初始化缓存
+func initCache(cfg *config.Config) (cache.Cache, error) {
+	return app.NewCache(&cfg.Redis)
 }
 
-// handleGracefulShutdown 处理优雅关闭
-// 学习要点：信号处理，资源清理，优雅关闭模式
-func handleGracefulShutdown() {
-	// 创建信号通道
-	quit := make(chan os.Signal, 1)
+// gracefulShutdown 优雅关闭
+// 学习要点：关闭顺序与启动顺序相反——先摘流量，再等待进行中的请求处理完毕，
+// 然后停止后台任务（配置监听、链路追踪导出），最后才关闭请求会用到的数据库和Redis连接
+func gracefulShutdown(srv *http.Server, container *app.Container, stopWatch func(), shutdownTracing tracing.ShutdownFunc) error {
+	var errs []error
 	
-	// 监听系统信号
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	// 1. 就绪探针立即返回503，负载均衡不再转发新请求
+	container.Health.SetDraining()
+	if delay := container.Config.Server.GetDrainDelay(); delay > 0 {
+		slog.Info("等待负载均衡摘除实例", "delay", delay)
+		time.Sleep(delay)
+	}
 	
-	// 等待信号
-	<-quit
-	fmt.Println("\n🛑 收到关闭信号，开始优雅关闭...")
+	// 2. 停止接收新连接，等待进行中的请求处理完毕
+	timeout := container.Config.Server.GetShutdownTimeout()
+	slog.Info("等待现有请求处理完毕", "timeout", timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		// 超过等待时间仍未完成的请求只能强制断开
+		slog.Error("等待请求处理超时，强制关闭连接", "error", err)
+		errs = append(errs, err, srv.Close())
+	} else {
+		slog.Info("HTTP服务器已停止")
+	}
 	
-	// 设置关闭超时
-	timeout := 30 * time.Second
-	fmt.Printf("⏰ 等待现有连接处理完毕（最多等待 %v）...\n", timeout)
+	// 3. 停止后台任务：先停止配置监听，避免热更新回调在连接关闭后执行，
+	// 再导出缓冲区中尚未发送的 Span
+	stopWatch()
+	slog.Info("已停止监听配置文件")
 	
-	// 关闭数据库连接
-	if err := database.Close(); err != nil {
-		fmt.Printf("❌ 关闭数据库连接失败: %v\n", err)
-	} else {
-		fmt.Println("✅ 数据库连接已关闭")
+	// 使用单独的超时，避免请求等待耗尽时间后 Span 来不及导出
+	tracingCtx, tracingCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer tracingCancel()
+	if err := shutdownTracing(tracingCtx); err != nil {
+		slog.Error("关闭链路追踪失败", "error", err)
+		errs = append(errs, err)
 	}
 	
-	// 关闭Redis连接
-	if err := redis.Close(); err != nil {
-		fmt.Printf("❌ 关闭Redis连接失败: %v\n", err)
+	// 4. 请求全部结束后再关闭数据库和缓存连接
+	if err := container.Close(); err != nil {
+		slog.Error("关闭连接失败", "error", err)
+		errs = append(errs, err)
 	} else {
-		fmt.Println("✅ Redis连接已关闭")
+		slog.Info("数据库和缓存连接已关闭")
 	}
 	
-	fmt.Println("👋 服务器已优雅关闭")
-	os.Exit(0)
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	slog.Info("服务器已优雅关闭")
+	return nil
+}
+
+// fatal 记录错误日志后退出
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
 
 // printBanner 打印启动横幅
@@ -187,5 +325,6 @@ func GetBuildInfo() map[string]string {
 		"version":    Version,
 		"build_time": BuildTime,
 		"git_commit": GitCommit,
+		"go_version": runtime.Version(),
 	}
 }
\ No newline at end of file
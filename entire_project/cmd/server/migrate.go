@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"task-management-system/internal/database"
+	"task-management-system/pkg/logger"
+)
+
+// migrateUsage 迁移子命令用法
+const migrateUsage = `用法: server migrate <命令>
+
+命令:
+  up          执行所有未执行的迁移
+  down        回滚最近执行的一个迁移
+  status      查看迁移执行状态
+  to N        迁移到版本 N（N 为 0 表示回滚全部）
+  baseline N  把不高于版本 N 的迁移标记为已执行但不执行脚本（接入 AutoMigrate 建好的数据库）`
+
+// runMigrate 执行数据库迁移子命令
+// 学习要点：迁移和服务使用同一个二进制和同一份配置，部署时可以先单独执行迁移再滚动发布
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("缺少迁移命令\n%s", migrateUsage)
+	}
+
+	cfg, err := initConfig()
+	if err != nil {
+		return err
+	}
+	if err := logger.Init(&cfg.Log); err != nil {
+		return err
+	}
+
+	db, err := database.Open(&cfg.Database.MySQL)
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		return migrator.Down(ctx)
+	case "status":
+		return printMigrationStatus(ctx, migrator)
+	case "to", "baseline":
+		if len(args) < 2 {
+			return fmt.Errorf("缺少目标版本\n%s", migrateUsage)
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || version < 0 {
+			return fmt.Errorf("目标版本不合法: %s", args[1])
+		}
+		if args[0] == "baseline" {
+			return migrator.Baseline(ctx, version)
+		}
+		return migrator.To(ctx, version)
+	default:
+		return fmt.Errorf("未知的迁移命令: %s\n%s", args[0], migrateUsage)
+	}
+}
+
+// printMigrationStatus 以表格形式打印迁移状态
+func printMigrationStatus(ctx context.Context, migrator *database.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "版本\t名称\t状态\t执行时间")
+	for _, status := range statuses {
+		state, appliedAt := "未执行", "-"
+		if status.AppliedAt != nil {
+			state = "已执行"
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
@@ -55,6 +55,11 @@ func (m *MockUserDAO) Update(ctx context.Context, user *models.User) error {
 	return args.Error(0)
 }
 
+func (m *MockUserDAO) UpdatePassword(ctx context.Context, id uint, hashedPassword string) error {
+	args := m.Called(ctx, id, hashedPassword)
+	return args.Error(0)
+}
+
 func (m *MockUserDAO) Delete(ctx context.Context, id uint) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -90,6 +95,16 @@ func (m *MockUserDAO) CountByStatus(ctx context.Context, status int) (int64, err
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockUserDAO) CountActiveSince(ctx context.Context, since time.Time) (int64, error) {
+	args := m.Called(ctx, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserDAO) GetRoleStats(ctx context.Context) (map[string]int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
 func (m *MockUserDAO) BatchCreate(ctx context.Context, users []models.User) error {
 	args := m.Called(ctx, users)
 	return args.Error(0)
@@ -0,0 +1,117 @@
+// Package app 应用容器
+// 学习要点：依赖注入（构造函数注入），所有依赖在启动时集中创建一次，
+// 再显式传给服务和处理器，避免各层通过包级全局变量隐式获取
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"task-management-system/internal/auth"
+	"task-management-system/internal/config"
+	"task-management-system/internal/database"
+	"task-management-system/internal/health"
+	"task-management-system/internal/services"
+	"task-management-system/pkg/cache"
+	"task-management-system/pkg/ratelimit"
+	"task-management-system/pkg/redis"
+)
+
+// Container 应用容器，持有基础设施和所有服务实例
+type Container struct {
+	Config       *config.Config
+	Live         *config.Live // 可热更新的配置（缓存有效期），由配置文件监听更新
+	DB           *gorm.DB
+	Cache        cache.Cache
+	TokenManager *auth.TokenManager
+	RateLimiter  *ratelimit.Limiter // 限流器，Redis 故障时退化为进程内计数
+	Health       *health.Checker    // 就绪检查（MySQL 和缓存）
+	BuildInfo    map[string]string  // 构建信息，由 main 注入
+
+	UserService    *services.UserService
+	TaskService    *services.TaskService
+	TagService     *services.TagService
+	CommentService *services.CommentService
+	ProjectService *services.ProjectService
+	AuthService    *services.AuthService
+}
+
+// NewContainer 使用已建立的数据库连接和缓存创建应用容器
+// 学习要点：容器本身不负责建立连接，测试时可以传入测试数据库和缓存
+func NewContainer(cfg *config.Config, db *gorm.DB, cache cache.Cache) *Container {
+	tokenManager := auth.NewTokenManager(cfg.Auth)
+	live := config.NewLive(cfg)
+
+	checks := []health.Check{{
+		Name: "mysql",
+		Ping: func(ctx context.Context) error { return database.Ping(ctx, db) },
+	}}
+	if cache != nil {
+		checks = append(checks, health.Check{Name: cfg.Redis.GetDriver(), Ping: cache.Ping})
+	}
+	authService := services.NewAuthService(db, cache, tokenManager, cfg.Auth)
+
+	return &Container{
+		Config:       cfg,
+		Live:         live,
+		DB:           db,
+		Cache:        cache,
+		TokenManager: tokenManager,
+		RateLimiter:  newRateLimiter(cfg, cache),
+		Health:       health.NewChecker(health.DefaultCheckTimeout, checks...),
+		BuildInfo:    map[string]string{},
+
+		UserService:    services.NewUserService(db, cache, live, authService),
+		TaskService:    services.NewTaskService(db, cache, live),
+		TagService:     services.NewTagService(db),
+		CommentService: services.NewCommentService(db),
+		ProjectService: services.NewProjectService(db),
+		AuthService:    authService,
+	}
+}
+
+// Close 释放容器持有的数据库和缓存连接
+func (c *Container) Close() error {
+	var errs []error
+	if err := database.Close(c.DB); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Cache != nil {
+		if err := c.Cache.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newRateLimiter 创建限流器
+// 学习要点：使用 Redis 时多实例共享限额，并准备一个进程内缓存作为故障时的后备
+func newRateLimiter(cfg *config.Config, store cache.Cache) *ratelimit.Limiter {
+	switch {
+	case store == nil:
+		return ratelimit.New(cache.NewMemoryCache(cfg.Redis.MemoryMaxEntries), nil)
+	case cfg.Redis.GetDriver() == config.CacheDriverMemory:
+		return ratelimit.New(store, nil)
+	default:
+		return ratelimit.New(store, cache.NewMemoryCache(cfg.Redis.MemoryMaxEntries))
+	}
+}
+
+// NewCache 根据配置创建缓存实现
+// 学习要点：工厂函数，调用方只拿到 cache.Cache 接口，不关心具体实现
+func NewCache(cfg *config.RedisConfig) (cache.Cache, error) {
+	switch cfg.GetDriver() {
+	case config.CacheDriverRedis:
+		client, err := redis.NewClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return redis.NewCacheService(client), nil
+	case config.CacheDriverMemory:
+		return cache.NewMemoryCache(cfg.MemoryMaxEntries), nil
+	default:
+		return nil, fmt.Errorf("不支持的缓存驱动: %s", cfg.Driver)
+	}
+}
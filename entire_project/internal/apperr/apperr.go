@@ -0,0 +1,136 @@
+// Package apperr 业务错误定义
+// 学习要点：各层只返回带类别的错误，由统一的错误中间件翻译成HTTP状态码和错误码，
+// 处理器不再逐个比较错误字符串
+package apperr
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// 错误类别
+// 学习要点：类别本身也是 error，业务错误通过 Unwrap 关联到类别，用 errors.Is 判断
+var (
+	ErrNotFound        = errors.New("资源不存在")
+	ErrConflict        = errors.New("资源冲突")
+	ErrForbidden       = errors.New("权限不足")
+	ErrValidation      = errors.New("请求参数错误")
+	ErrUnauthorized    = errors.New("未登录或登录已失效")
+	ErrTooManyRequests = errors.New("请求过于频繁")
+	ErrUnavailable     = errors.New("服务暂不可用")
+	ErrTimeout         = errors.New("请求超时")
+)
+
+// 未归类错误的错误码
+const CodeInternal = "internal_error"
+
+// kindMapping 错误类别与HTTP状态码、默认错误码的对应关系
+var kindMapping = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{ErrNotFound, http.StatusNotFound, "not_found"},
+	{ErrConflict, http.StatusConflict, "conflict"},
+	{ErrForbidden, http.StatusForbidden, "forbidden"},
+	{ErrValidation, http.StatusBadRequest, "invalid_request"},
+	{ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+	{ErrTooManyRequests, http.StatusTooManyRequests, "too_many_requests"},
+	{ErrUnavailable, http.StatusServiceUnavailable, "service_unavailable"},
+	{ErrTimeout, http.StatusGatewayTimeout, "request_timeout"},
+	{context.DeadlineExceeded, http.StatusGatewayTimeout, "request_timeout"},
+}
+
+// Error 业务错误
+type Error struct {
+	Kind    error  // 错误类别
+	Code    string // 稳定的机器可读错误码，如 task_not_found
+	Message string // 面向用户的错误信息
+	Err     error  // 底层错误（可选）
+}
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap 同时暴露错误类别和底层错误，errors.Is 对两者都能匹配
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+// New 创建业务错误
+func New(kind error, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Wrap 用业务错误包装底层错误
+func Wrap(err error, kind error, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message, Err: err}
+}
+
+// NotFound 创建资源不存在错误
+func NotFound(code, message string) *Error {
+	return New(ErrNotFound, code, message)
+}
+
+// Conflict 创建资源冲突错误
+func Conflict(code, message string) *Error {
+	return New(ErrConflict, code, message)
+}
+
+// Forbidden 创建权限不足错误
+func Forbidden(code, message string) *Error {
+	return New(ErrForbidden, code, message)
+}
+
+// Validation 创建参数校验错误
+func Validation(code, message string) *Error {
+	return New(ErrValidation, code, message)
+}
+
+// Unauthorized 创建未认证错误
+func Unauthorized(code, message string) *Error {
+	return New(ErrUnauthorized, code, message)
+}
+
+// Unavailable 创建服务不可用错误
+func Unavailable(code, message string) *Error {
+	return New(ErrUnavailable, code, message)
+}
+
+// HTTPStatus 返回错误对应的HTTP状态码，未归类的错误返回500
+func HTTPStatus(err error) int {
+	for _, m := range kindMapping {
+		if errors.Is(err, m.kind) {
+			return m.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// Code 返回错误码：优先使用业务错误自带的错误码，其次使用类别的默认错误码
+func Code(err error) string {
+	var appErr *Error
+	if errors.As(err, &appErr) && appErr.Code != "" {
+		return appErr.Code
+	}
+	for _, m := range kindMapping {
+		if errors.Is(err, m.kind) {
+			return m.code
+		}
+	}
+	return CodeInternal
+}
+
+// IsInternal 判断是否为未归类的内部错误（详细信息不应返回给客户端）
+func IsInternal(err error) bool {
+	return HTTPStatus(err) == http.StatusInternalServerError
+}
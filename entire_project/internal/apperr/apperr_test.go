@@ -0,0 +1,46 @@
+package apperr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPStatusAndCode(t *testing.T) {
+	taskNotFound := NotFound("task_not_found", "任务不存在")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"业务错误", taskNotFound, http.StatusNotFound, "task_not_found"},
+		{"fmt包装后的业务错误", fmt.Errorf("%w: ID=1", taskNotFound), http.StatusNotFound, "task_not_found"},
+		{"包装底层错误", Wrap(errors.New("EOF"), ErrValidation, "invalid_request", "请求参数错误"), http.StatusBadRequest, "invalid_request"},
+		{"只有类别", fmt.Errorf("%w: 名称重复", ErrConflict), http.StatusConflict, "conflict"},
+		{"上下文超时", fmt.Errorf("查询失败: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, "request_timeout"},
+		{"未归类错误", errors.New("connection refused"), http.StatusInternalServerError, CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantStatus, HTTPStatus(tt.err))
+			assert.Equal(t, tt.wantCode, Code(tt.err))
+		})
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	cause := errors.New("EOF")
+	err := Wrap(cause, ErrUnavailable, "cache_unavailable", "缓存不可用")
+
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "缓存不可用: EOF", err.Error())
+	assert.False(t, IsInternal(err))
+}
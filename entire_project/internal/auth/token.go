@@ -0,0 +1,156 @@
+// Package auth 认证模块
+// 学习要点：JWT 的签发与校验，访问令牌与刷新令牌的分工
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/config"
+	"task-management-system/pkg/utils"
+)
+
+// 令牌类型常量
+// 学习要点：访问令牌短期有效用于调用接口，刷新令牌长期有效只用于换取新令牌
+const (
+	TokenTypeAccess  = "access"  // 访问令牌
+	TokenTypeRefresh = "refresh" // 刷新令牌
+)
+
+// ErrInvalidToken 令牌无效（签名错误、已过期或类型不匹配）
+var ErrInvalidToken = apperr.Unauthorized("invalid_token", "令牌无效或已过期")
+
+// Claims JWT 载荷
+// 学习要点：自定义声明 + 标准声明（过期时间、签发者、唯一ID）
+type Claims struct {
+	UserID    uint   `json:"uid"`  // 用户ID
+	Username  string `json:"name"` // 用户名
+	Role      string `json:"role"` // 角色
+	TokenType string `json:"typ"`  // 令牌类型
+	// 签发时间（纳秒）：标准的 iat 只精确到秒，强制下线后同一秒内重新登录签发的令牌需要据此区分
+	IssuedAtNano int64 `json:"iat_ns"`
+	jwt.RegisteredClaims
+}
+
+// TokenPair 访问令牌与刷新令牌组合
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`       // 访问令牌
+	RefreshToken     string    `json:"refresh_token"`      // 刷新令牌
+	TokenType        string    `json:"token_type"`         // 令牌类型（Bearer）
+	ExpiresAt        time.Time `json:"expires_at"`         // 访问令牌过期时间
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"` // 刷新令牌过期时间
+	RefreshTokenID   string    `json:"-"`                  // 刷新令牌ID（用于吊销，不返回给前端）
+}
+
+// TokenManager 令牌管理器
+// 学习要点：把签名密钥和有效期集中管理，处理器和中间件共用同一个实例
+type TokenManager struct {
+	secret     []byte
+	issuer     string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewTokenManager 创建令牌管理器
+func NewTokenManager(cfg config.AuthConfig) *TokenManager {
+	return &TokenManager{
+		secret:     []byte(cfg.Secret),
+		issuer:     cfg.Issuer,
+		accessTTL:  cfg.GetAccessTokenTTL(),
+		refreshTTL: cfg.GetRefreshTokenTTL(),
+	}
+}
+
+// RefreshTTL 获取刷新令牌有效期（用于设置Redis中吊销记录的过期时间）
+func (m *TokenManager) RefreshTTL() time.Duration {
+	return m.refreshTTL
+}
+
+// GenerateTokenPair 为用户签发一对令牌
+// 学习要点：角色写入令牌，权限中间件无需每次查询数据库
+func (m *TokenManager) GenerateTokenPair(userID uint, username, role string) (*TokenPair, error) {
+	now := time.Now()
+
+	accessToken, accessExp, _, err := m.sign(userID, username, role, TokenTypeAccess, now, m.accessTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshExp, refreshID, err := m.sign(userID, username, role, TokenTypeRefresh, now, m.refreshTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		ExpiresAt:        accessExp,
+		RefreshExpiresAt: refreshExp,
+		RefreshTokenID:   refreshID,
+	}, nil
+}
+
+// ParseAccessToken 解析并校验访问令牌
+func (m *TokenManager) ParseAccessToken(tokenString string) (*Claims, error) {
+	return m.parse(tokenString, TokenTypeAccess)
+}
+
+// ParseRefreshToken 解析并校验刷新令牌
+func (m *TokenManager) ParseRefreshToken(tokenString string) (*Claims, error) {
+	return m.parse(tokenString, TokenTypeRefresh)
+}
+
+// sign 签发单个令牌
+// 学习要点：使用 HS256 对称签名，jti 作为令牌唯一标识
+func (m *TokenManager) sign(userID uint, username, role, tokenType string, now time.Time, ttl time.Duration) (string, time.Time, string, error) {
+	expiresAt := now.Add(ttl)
+	tokenID := utils.GenerateUUID()
+
+	claims := Claims{
+		UserID:       userID,
+		Username:     username,
+		Role:         role,
+		TokenType:    tokenType,
+		IssuedAtNano: now.UnixNano(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			Issuer:    m.issuer,
+			Subject:   fmt.Sprintf("%d", userID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(m.secret)
+	if err != nil {
+		return "", time.Time{}, "", fmt.Errorf("签发令牌失败: %w", err)
+	}
+
+	return signed, expiresAt, tokenID, nil
+}
+
+// parse 解析令牌并校验签名算法、过期时间和令牌类型
+func (m *TokenManager) parse(tokenString, expectedType string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		// 防止算法替换攻击：只接受 HMAC 签名
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("不支持的签名算法: %v", t.Header["alg"])
+		}
+		return m.secret, nil
+	}, jwt.WithIssuer(m.issuer))
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.TokenType != expectedType {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-management-system/internal/config"
+)
+
+func newTestManager(secret string) *TokenManager {
+	return NewTokenManager(config.AuthConfig{
+		Secret:          secret,
+		Issuer:          "test",
+		AccessTokenTTL:  60,
+		RefreshTokenTTL: 120,
+	})
+}
+
+func TestTokenManager_GenerateAndParse(t *testing.T) {
+	m := newTestManager("secret")
+
+	pair, err := m.GenerateTokenPair(42, "alice", "member")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", pair.TokenType)
+	assert.NotEmpty(t, pair.RefreshTokenID)
+
+	claims, err := m.ParseAccessToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, uint(42), claims.UserID)
+	assert.Equal(t, "alice", claims.Username)
+	assert.Equal(t, "member", claims.Role)
+
+	refreshClaims, err := m.ParseRefreshToken(pair.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, pair.RefreshTokenID, refreshClaims.ID)
+}
+
+func TestTokenManager_Parse_Invalid(t *testing.T) {
+	m := newTestManager("secret")
+	pair, err := m.GenerateTokenPair(1, "bob", "viewer")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		parse func(string) (*Claims, error)
+		token string
+	}{
+		{"刷新令牌不能当作访问令牌", m.ParseAccessToken, pair.RefreshToken},
+		{"访问令牌不能当作刷新令牌", m.ParseRefreshToken, pair.AccessToken},
+		{"其他密钥签发的令牌", newTestManager("other").ParseAccessToken, pair.AccessToken},
+		{"格式错误的令牌", m.ParseAccessToken, "not-a-jwt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.parse(tt.token)
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+}
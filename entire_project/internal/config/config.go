@@ -1,95 +1,175 @@
 // Package config 配置管理模块
-// 学习要点：使用 viper 进行配置管理，支持多种配置格式
+// 学习要点：使用 viper 进行分层配置管理，优先级从低到高依次为：
+// 基础配置文件 < 环境配置文件(config.<profile>.yaml) < 环境变量 < 密钥文件
 package config
 
 import (
 	"fmt"
 	"time"
-
-	"github.com/spf13/viper"
 )
 
 // Config 应用程序配置结构体
-// 学习要点：使用结构体标签进行配置映射，支持多种配置源
+// 学习要点：viper 解析时只识别 mapstructure 标签，yaml 标签用于导出配置（config dump）
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`   // 服务器配置
-	Database DatabaseConfig `yaml:"database"` // 数据库配置
-	Redis    RedisConfig    `yaml:"redis"`    // Redis配置
-	Log      LogConfig      `yaml:"log"`      // 日志配置
+	Server          ServerConfig          `yaml:"server" mapstructure:"server"`                     // 服务器配置
+	Database        DatabaseConfig        `yaml:"database" mapstructure:"database"`                 // 数据库配置
+	Redis           RedisConfig           `yaml:"redis" mapstructure:"redis"`                       // Redis配置
+	Cache           CacheConfig           `yaml:"cache" mapstructure:"cache"`                       // 缓存有效期配置
+	Log             LogConfig             `yaml:"log" mapstructure:"log"`                           // 日志配置
+	Auth            AuthConfig            `yaml:"auth" mapstructure:"auth"`                         // 认证配置
+	Tracing         TracingConfig         `yaml:"tracing" mapstructure:"tracing"`                   // 链路追踪配置
+	RateLimit       RateLimitConfig       `yaml:"rate_limit" mapstructure:"rate_limit"`             // 限流配置
+	CORS            CORSConfig            `yaml:"cors" mapstructure:"cors"`                         // 跨域配置
+	SecurityHeaders SecurityHeadersConfig `yaml:"security_headers" mapstructure:"security_headers"` // 安全响应头配置
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
-	Port int    `yaml:"port"` // 服务端口
-	Mode string `yaml:"mode"` // 运行模式
+	Port           int    `yaml:"port" mapstructure:"port"`                       // 服务端口
+	Mode           string `yaml:"mode" mapstructure:"mode"`                       // 运行模式
+	RequestTimeout int    `yaml:"request_timeout" mapstructure:"request_timeout"` // 单个请求的处理超时(秒)
+
+	// HTTP 服务器与优雅关闭配置
+	ReadTimeout     int `yaml:"read_timeout" mapstructure:"read_timeout"`         // 读取请求（含请求体）的超时(秒)
+	WriteTimeout    int `yaml:"write_timeout" mapstructure:"write_timeout"`       // 写出响应的超时(秒)，应大于 request_timeout
+	IdleTimeout     int `yaml:"idle_timeout" mapstructure:"idle_timeout"`         // keep-alive 空闲连接的超时(秒)
+	ShutdownTimeout int `yaml:"shutdown_timeout" mapstructure:"shutdown_timeout"` // 优雅关闭时等待请求处理完毕的最长时间(秒)
+	DrainDelay      int `yaml:"drain_delay" mapstructure:"drain_delay"`           // 收到关闭信号后先标记未就绪，等待多久再停止接收新连接(秒)
 }
 
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
-	MySQL MySQLConfig `yaml:"mysql"` // MySQL配置
+	MySQL MySQLConfig `yaml:"mysql" mapstructure:"mysql"` // MySQL配置
 }
 
 // MySQLConfig MySQL数据库配置
 // 学习要点：数据库连接参数的配置管理
 type MySQLConfig struct {
-	Host            string `yaml:"host"`              // 主机地址
-	Port            int    `yaml:"port"`              // 端口号
-	Username        string `yaml:"username"`          // 用户名
-	Password        string `yaml:"password"`          // 密码
-	DBName          string `yaml:"dbname"`            // 数据库名
-	Charset         string `yaml:"charset"`           // 字符集
-	ParseTime       bool   `yaml:"parse_time"`        // 解析时间
-	Loc             string `yaml:"loc"`               // 时区
-	MaxIdleConns    int    `yaml:"max_idle_conns"`    // 最大空闲连接数
-	MaxOpenConns    int    `yaml:"max_open_conns"`    // 最大打开连接数
-	ConnMaxLifetime int    `yaml:"conn_max_lifetime"` // 连接最大生命周期
+	Host            string `yaml:"host" mapstructure:"host"`                           // 主机地址
+	Port            int    `yaml:"port" mapstructure:"port"`                           // 端口号
+	Username        string `yaml:"username" mapstructure:"username"`                   // 用户名
+	Password        string `yaml:"password" mapstructure:"password"`                   // 密码
+	PasswordFile    string `yaml:"password_file" mapstructure:"password_file"`         // 密码文件，设置后覆盖 password
+	DBName          string `yaml:"dbname" mapstructure:"dbname"`                       // 数据库名
+	Charset         string `yaml:"charset" mapstructure:"charset"`                     // 字符集
+	ParseTime       bool   `yaml:"parse_time" mapstructure:"parse_time"`               // 解析时间
+	Loc             string `yaml:"loc" mapstructure:"loc"`                             // 时区
+	MaxIdleConns    int    `yaml:"max_idle_conns" mapstructure:"max_idle_conns"`       // 最大空闲连接数
+	MaxOpenConns    int    `yaml:"max_open_conns" mapstructure:"max_open_conns"`       // 最大打开连接数
+	ConnMaxLifetime int    `yaml:"conn_max_lifetime" mapstructure:"conn_max_lifetime"` // 连接最大生命周期
 }
 
 // RedisConfig Redis配置
-// 学习要点：Redis 连接池配置
+// 学习要点：Redis 连接池配置；Driver 为 memory 时使用进程内缓存，无需启动 Redis
 type RedisConfig struct {
-	Host         string `yaml:"host"`           // 主机地址
-	Port         int    `yaml:"port"`           // 端口号
-	Password     string `yaml:"password"`       // 密码
-	DB           int    `yaml:"db"`             // 数据库编号
-	PoolSize     int    `yaml:"pool_size"`      // 连接池大小
-	MinIdleConns int    `yaml:"min_idle_conns"` // 最小空闲连接数
+	Driver           string `yaml:"driver" mapstructure:"driver"`                         // 缓存驱动：redis（默认）或 memory
+	MemoryMaxEntries int    `yaml:"memory_max_entries" mapstructure:"memory_max_entries"` // 内存缓存最多保存的键数量
+	Host             string `yaml:"host" mapstructure:"host"`                             // 主机地址
+	Port             int    `yaml:"port" mapstructure:"port"`                             // 端口号
+	Password         string `yaml:"password" mapstructure:"password"`                     // 密码
+	PasswordFile     string `yaml:"password_file" mapstructure:"password_file"`           // 密码文件，设置后覆盖 password
+	DB               int    `yaml:"db" mapstructure:"db"`                                 // 数据库编号
+	PoolSize         int    `yaml:"pool_size" mapstructure:"pool_size"`                   // 连接池大小
+	MinIdleConns     int    `yaml:"min_idle_conns" mapstructure:"min_idle_conns"`         // 最小空闲连接数
 }
 
 // LogConfig 日志配置
 type LogConfig struct {
-	Level      string `yaml:"level"`       // 日志级别
-	FilePath   string `yaml:"file_path"`   // 日志文件路径
-	MaxSize    int    `yaml:"max_size"`    // 单个日志文件最大大小
-	MaxBackups int    `yaml:"max_backups"` // 保留的日志文件数量
-	MaxAge     int    `yaml:"max_age"`     // 日志文件保留天数
+	Level      string `yaml:"level" mapstructure:"level"`             // 日志级别
+	Format     string `yaml:"format" mapstructure:"format"`           // 输出格式：json（默认）或 console
+	FilePath   string `yaml:"file_path" mapstructure:"file_path"`     // 日志文件路径
+	MaxSize    int    `yaml:"max_size" mapstructure:"max_size"`       // 单个日志文件最大大小
+	MaxBackups int    `yaml:"max_backups" mapstructure:"max_backups"` // 保留的日志文件数量
+	MaxAge     int    `yaml:"max_age" mapstructure:"max_age"`         // 日志文件保留天数
 }
 
-// GlobalConfig 全局配置实例
-var GlobalConfig *Config
+// AuthConfig 认证配置
+// 学习要点：JWT 签名密钥和令牌有效期的配置
+type AuthConfig struct {
+	Secret          string `yaml:"secret" mapstructure:"secret"`                       // JWT签名密钥
+	SecretFile      string `yaml:"secret_file" mapstructure:"secret_file"`             // 签名密钥文件，设置后覆盖 secret
+	Issuer          string `yaml:"issuer" mapstructure:"issuer"`                       // 令牌签发者
+	AccessTokenTTL  int    `yaml:"access_token_ttl" mapstructure:"access_token_ttl"`   // 访问令牌有效期(秒)
+	RefreshTokenTTL int    `yaml:"refresh_token_ttl" mapstructure:"refresh_token_ttl"` // 刷新令牌有效期(秒)
 
-// Load 加载配置文件
-// 学习要点：配置文件的读取和解析，错误处理
-func Load(configPath string) error {
-	viper.SetConfigFile(configPath)
-	viper.SetConfigType("yaml")
+	// 登录防爆破配置
+	MaxLoginAttempts      int `yaml:"max_login_attempts" mapstructure:"max_login_attempts"`               // 同一用户名连续失败多少次后锁定
+	MaxLoginAttemptsPerIP int `yaml:"max_login_attempts_per_ip" mapstructure:"max_login_attempts_per_ip"` // 同一IP连续失败多少次后锁定
+	LoginAttemptWindow    int `yaml:"login_attempt_window" mapstructure:"login_attempt_window"`           // 失败次数统计窗口(秒)
+	LockoutDuration       int `yaml:"lockout_duration" mapstructure:"lockout_duration"`                   // 首次锁定时长(秒)，之后每次翻倍
+	MaxLockoutDuration    int `yaml:"max_lockout_duration" mapstructure:"max_lockout_duration"`           // 最长锁定时长(秒)
+}
 
-	// 设置环境变量前缀
-	viper.SetEnvPrefix("TASK")
-	viper.AutomaticEnv()
+// CacheConfig 缓存有效期配置
+// 学习要点：有效期属于可热更新的配置，修改配置文件后无需重启即可生效
+type CacheConfig struct {
+	TaskTTL        int `yaml:"task_ttl" mapstructure:"task_ttl"`                 // 任务详情缓存(秒)
+	TaskCountTTL   int `yaml:"task_count_ttl" mapstructure:"task_count_ttl"`     // 用户任务数缓存(秒)
+	UserTTL        int `yaml:"user_ttl" mapstructure:"user_ttl"`                 // 用户详情缓存(秒)
+	UserSearchTTL  int `yaml:"user_search_ttl" mapstructure:"user_search_ttl"`   // 用户搜索结果缓存(秒)
+	ActiveUsersTTL int `yaml:"active_users_ttl" mapstructure:"active_users_ttl"` // 活跃用户列表缓存(秒)
+	StatsTTL       int `yaml:"stats_ttl" mapstructure:"stats_ttl"`               // 统计数据缓存(秒)
+}
 
-	// 读取配置文件
-	if err := viper.ReadInConfig(); err != nil {
-		return fmt.Errorf("读取配置文件失败: %w", err)
-	}
+// TracingConfig 链路追踪配置
+// 学习要点：导出器可选 otlp（发送到 Collector/Jaeger）、stdout 或 file，后两者无需外部服务
+type TracingConfig struct {
+	Enabled     bool    `yaml:"enabled" mapstructure:"enabled"`           // 是否启用
+	Exporter    string  `yaml:"exporter" mapstructure:"exporter"`         // 导出器：otlp（默认）、stdout、file
+	Endpoint    string  `yaml:"endpoint" mapstructure:"endpoint"`         // OTLP HTTP 地址，如 localhost:4318
+	Insecure    bool    `yaml:"insecure" mapstructure:"insecure"`         // OTLP 是否使用明文 HTTP
+	FilePath    string  `yaml:"file_path" mapstructure:"file_path"`       // file 导出器的输出文件
+	ServiceName string  `yaml:"service_name" mapstructure:"service_name"` // 服务名
+	SampleRatio float64 `yaml:"sample_ratio" mapstructure:"sample_ratio"` // 采样比例(0~1]
+}
 
-	// 解析配置到结构体
-	GlobalConfig = &Config{}
-	if err := viper.Unmarshal(GlobalConfig); err != nil {
-		return fmt.Errorf("解析配置文件失败: %w", err)
-	}
+// RateLimitConfig 限流配置
+// 学习要点：不同路由组的查询代价不同，分别配置限额；已登录按用户限流，未登录按IP限流
+type RateLimitConfig struct {
+	Enabled bool          `yaml:"enabled" mapstructure:"enabled"` // 是否启用
+	User    RateLimitRule `yaml:"user" mapstructure:"user"`       // 用户接口 /api/v1/users
+	Task    RateLimitRule `yaml:"task" mapstructure:"task"`       // 任务和标签接口 /api/v1/tasks、/api/v1/tags
+	Admin   RateLimitRule `yaml:"admin" mapstructure:"admin"`     // 管理员接口 /api/admin
+}
 
-	return nil
+// RateLimitRule 单个路由组的限流规则：每个时间窗口内最多允许的请求数
+type RateLimitRule struct {
+	Requests int `yaml:"requests" mapstructure:"requests"` // 窗口内最多请求数
+	Window   int `yaml:"window" mapstructure:"window"`     // 窗口长度(秒)
+}
+
+// CORSConfig 跨域配置
+// 学习要点：允许携带认证信息时不能使用 Access-Control-Allow-Origin: *，
+// 只能把请求的 Origin 与白名单比较，匹配时原样返回
+type CORSConfig struct {
+	AllowedOrigins   []string          `yaml:"allowed_origins" mapstructure:"allowed_origins"`     // 允许的来源，支持 https://*.example.com 匹配任意子域名
+	AllowedMethods   []string          `yaml:"allowed_methods" mapstructure:"allowed_methods"`     // 允许的方法
+	AllowedHeaders   []string          `yaml:"allowed_headers" mapstructure:"allowed_headers"`     // 允许的请求头
+	ExposedHeaders   []string          `yaml:"exposed_headers" mapstructure:"exposed_headers"`     // 浏览器可以读取的响应头
+	AllowCredentials bool              `yaml:"allow_credentials" mapstructure:"allow_credentials"` // 是否允许携带 Cookie 等认证信息
+	MaxAge           int               `yaml:"max_age" mapstructure:"max_age"`                     // 预检结果缓存时间(秒)
+	Routes           []CORSRouteConfig `yaml:"routes" mapstructure:"routes"`                       // 按路径前缀覆盖的规则
+}
+
+// CORSRouteConfig 按路径前缀覆盖的跨域规则
+// 学习要点：按路径而不是在路由上配置，预检请求（OPTIONS）不会匹配到具体路由也能找到规则；
+// 来源和认证信息整体替代全局配置，方法和请求头为空时沿用全局配置
+type CORSRouteConfig struct {
+	PathPrefix       string   `yaml:"path_prefix" mapstructure:"path_prefix"`             // 路径前缀，如 /api/v1/public
+	AllowedOrigins   []string `yaml:"allowed_origins" mapstructure:"allowed_origins"`     // 允许的来源
+	AllowCredentials bool     `yaml:"allow_credentials" mapstructure:"allow_credentials"` // 是否允许携带认证信息
+	AllowedMethods   []string `yaml:"allowed_methods" mapstructure:"allowed_methods"`     // 允许的方法
+	AllowedHeaders   []string `yaml:"allowed_headers" mapstructure:"allowed_headers"`     // 允许的请求头
+}
+
+// SecurityHeadersConfig 安全响应头配置
+type SecurityHeadersConfig struct {
+	Enabled               bool   `yaml:"enabled" mapstructure:"enabled"`                                 // 是否启用
+	HSTSMaxAge            int    `yaml:"hsts_max_age" mapstructure:"hsts_max_age"`                       // HSTS 有效期(秒)，0 表示不发送
+	HSTSIncludeSubdomains bool   `yaml:"hsts_include_subdomains" mapstructure:"hsts_include_subdomains"` // HSTS 是否包含子域名
+	FrameOptions          string `yaml:"frame_options" mapstructure:"frame_options"`                     // X-Frame-Options：DENY（默认）或 SAMEORIGIN
+	ReferrerPolicy        string `yaml:"referrer_policy" mapstructure:"referrer_policy"`                 // Referrer-Policy
+	ContentSecurityPolicy string `yaml:"content_security_policy" mapstructure:"content_security_policy"` // Content-Security-Policy，为空时不发送
 }
 
 // GetMySQLDSN 获取MySQL数据源名称
@@ -112,7 +192,290 @@ func (c *RedisConfig) GetRedisAddr() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
+// 缓存驱动常量
+const (
+	CacheDriverRedis  = "redis"  // Redis 缓存
+	CacheDriverMemory = "memory" // 进程内缓存（仅适用于单实例开发和测试）
+)
+
+// GetDriver 获取缓存驱动（默认 redis）
+func (c *RedisConfig) GetDriver() string {
+	if c.Driver == "" {
+		return CacheDriverRedis
+	}
+	return c.Driver
+}
+
+// IsDevMode 是否为开发模式（debug 或未配置）
+func (c *ServerConfig) IsDevMode() bool {
+	return c.Mode == "" || c.Mode == "debug"
+}
+
+// GetRequestTimeout 获取请求处理超时（未配置时默认10秒）
+func (c *ServerConfig) GetRequestTimeout() time.Duration {
+	if c.RequestTimeout <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(c.RequestTimeout) * time.Second
+}
+
+// GetReadTimeout 获取读取请求超时（默认15秒）
+func (c *ServerConfig) GetReadTimeout() time.Duration {
+	if c.ReadTimeout <= 0 {
+		return 15 * time.Second
+	}
+	return time.Duration(c.ReadTimeout) * time.Second
+}
+
+// GetWriteTimeout 获取写出响应超时（默认比请求处理超时多5秒，保证超时响应能够写出）
+func (c *ServerConfig) GetWriteTimeout() time.Duration {
+	if c.WriteTimeout <= 0 {
+		return c.GetRequestTimeout() + 5*time.Second
+	}
+	return time.Duration(c.WriteTimeout) * time.Second
+}
+
+// GetIdleTimeout 获取空闲连接超时（默认60秒）
+func (c *ServerConfig) GetIdleTimeout() time.Duration {
+	if c.IdleTimeout <= 0 {
+		return 60 * time.Second
+	}
+	return time.Duration(c.IdleTimeout) * time.Second
+}
+
+// GetShutdownTimeout 获取优雅关闭超时（默认30秒）
+func (c *ServerConfig) GetShutdownTimeout() time.Duration {
+	if c.ShutdownTimeout <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(c.ShutdownTimeout) * time.Second
+}
+
+// GetDrainDelay 获取摘流等待时间（默认不等待）
+func (c *ServerConfig) GetDrainDelay() time.Duration {
+	if c.DrainDelay <= 0 {
+		return 0
+	}
+	return time.Duration(c.DrainDelay) * time.Second
+}
+
 // GetConnMaxLifetime 获取连接最大生命周期
 func (c *MySQLConfig) GetConnMaxLifetime() time.Duration {
 	return time.Duration(c.ConnMaxLifetime) * time.Second
-}
\ No newline at end of file
+}
+
+// GetAccessTokenTTL 获取访问令牌有效期（未配置时默认15分钟）
+func (c *AuthConfig) GetAccessTokenTTL() time.Duration {
+	if c.AccessTokenTTL <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(c.AccessTokenTTL) * time.Second
+}
+
+// GetRefreshTokenTTL 获取刷新令牌有效期（未配置时默认7天）
+func (c *AuthConfig) GetRefreshTokenTTL() time.Duration {
+	if c.RefreshTokenTTL <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return time.Duration(c.RefreshTokenTTL) * time.Second
+}
+
+// GetMaxLoginAttempts 获取同一用户名允许的连续失败次数（默认5次）
+func (c *AuthConfig) GetMaxLoginAttempts() int64 {
+	if c.MaxLoginAttempts <= 0 {
+		return 5
+	}
+	return int64(c.MaxLoginAttempts)
+}
+
+// GetMaxLoginAttemptsPerIP 获取同一IP允许的连续失败次数（默认20次）
+func (c *AuthConfig) GetMaxLoginAttemptsPerIP() int64 {
+	if c.MaxLoginAttemptsPerIP <= 0 {
+		return 20
+	}
+	return int64(c.MaxLoginAttemptsPerIP)
+}
+
+// GetLoginAttemptWindow 获取失败次数统计窗口（默认15分钟）
+func (c *AuthConfig) GetLoginAttemptWindow() time.Duration {
+	if c.LoginAttemptWindow <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(c.LoginAttemptWindow) * time.Second
+}
+
+// GetLockoutDuration 获取首次锁定时长（默认1分钟）
+func (c *AuthConfig) GetLockoutDuration() time.Duration {
+	if c.LockoutDuration <= 0 {
+		return time.Minute
+	}
+	return time.Duration(c.LockoutDuration) * time.Second
+}
+
+// GetMaxLockoutDuration 获取最长锁定时长（默认1小时）
+func (c *AuthConfig) GetMaxLockoutDuration() time.Duration {
+	if c.MaxLockoutDuration <= 0 {
+		return time.Hour
+	}
+	return time.Duration(c.MaxLockoutDuration) * time.Second
+}
+
+// ttlOrDefault 秒数转为时长，未配置时使用默认值
+func ttlOrDefault(seconds int, def time.Duration) time.Duration {
+	if seconds <= 0 {
+		return def
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// GetTaskTTL 获取任务详情缓存有效期（默认1小时）
+func (c *CacheConfig) GetTaskTTL() time.Duration {
+	return ttlOrDefault(c.TaskTTL, time.Hour)
+}
+
+// GetTaskCountTTL 获取用户任务数缓存有效期（默认24小时）
+func (c *CacheConfig) GetTaskCountTTL() time.Duration {
+	return ttlOrDefault(c.TaskCountTTL, 24*time.Hour)
+}
+
+// GetUserTTL 获取用户详情缓存有效期（默认1小时）
+func (c *CacheConfig) GetUserTTL() time.Duration {
+	return ttlOrDefault(c.UserTTL, time.Hour)
+}
+
+// GetUserSearchTTL 获取用户搜索结果缓存有效期（默认5分钟）
+func (c *CacheConfig) GetUserSearchTTL() time.Duration {
+	return ttlOrDefault(c.UserSearchTTL, 5*time.Minute)
+}
+
+// GetActiveUsersTTL 获取活跃用户列表缓存有效期（默认30分钟）
+func (c *CacheConfig) GetActiveUsersTTL() time.Duration {
+	return ttlOrDefault(c.ActiveUsersTTL, 30*time.Minute)
+}
+
+// GetStatsTTL 获取统计数据缓存有效期（默认1分钟）
+// 学习要点：仪表盘数据允许短暂延迟，短TTL缓存即可大幅减少聚合查询
+func (c *CacheConfig) GetStatsTTL() time.Duration {
+	return ttlOrDefault(c.StatsTTL, time.Minute)
+}
+
+// 各路由组默认的每分钟请求数
+const (
+	DefaultUserRateLimit  = 60  // 用户接口
+	DefaultTaskRateLimit  = 120 // 任务和标签接口
+	DefaultAdminRateLimit = 30  // 管理员接口（统计查询代价较高）
+)
+
+// GetUser 获取用户接口的限流规则
+func (c *RateLimitConfig) GetUser() RateLimitRule {
+	return c.User.withDefaults(DefaultUserRateLimit)
+}
+
+// GetTask 获取任务和标签接口的限流规则
+func (c *RateLimitConfig) GetTask() RateLimitRule {
+	return c.Task.withDefaults(DefaultTaskRateLimit)
+}
+
+// GetAdmin 获取管理员接口的限流规则
+func (c *RateLimitConfig) GetAdmin() RateLimitRule {
+	return c.Admin.withDefaults(DefaultAdminRateLimit)
+}
+
+// withDefaults 未配置的字段使用默认值（窗口默认60秒）
+func (r RateLimitRule) withDefaults(requests int) RateLimitRule {
+	if r.Requests <= 0 {
+		r.Requests = requests
+	}
+	if r.Window <= 0 {
+		r.Window = 60
+	}
+	return r
+}
+
+// GetWindow 获取窗口长度
+func (r RateLimitRule) GetWindow() time.Duration {
+	return ttlOrDefault(r.Window, time.Minute)
+}
+
+// 跨域默认值
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+	defaultCORSHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-User-ID", "X-Request-ID"}
+	defaultCORSExposed = []string{"X-Request-ID", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After"}
+)
+
+// GetAllowedMethods 获取允许的方法（默认常用的全部方法）
+func (c *CORSConfig) GetAllowedMethods() []string {
+	if len(c.AllowedMethods) == 0 {
+		return defaultCORSMethods
+	}
+	return c.AllowedMethods
+}
+
+// GetAllowedHeaders 获取允许的请求头（默认包含 Authorization、X-User-ID、X-Request-ID）
+func (c *CORSConfig) GetAllowedHeaders() []string {
+	if len(c.AllowedHeaders) == 0 {
+		return defaultCORSHeaders
+	}
+	return c.AllowedHeaders
+}
+
+// GetExposedHeaders 获取浏览器可以读取的响应头（默认请求ID和限流头）
+func (c *CORSConfig) GetExposedHeaders() []string {
+	if len(c.ExposedHeaders) == 0 {
+		return defaultCORSExposed
+	}
+	return c.ExposedHeaders
+}
+
+// GetMaxAge 获取预检结果缓存时间（默认1天）
+func (c *CORSConfig) GetMaxAge() time.Duration {
+	return ttlOrDefault(c.MaxAge, 24*time.Hour)
+}
+
+// GetFrameOptions 获取 X-Frame-Options（默认 DENY）
+func (c *SecurityHeadersConfig) GetFrameOptions() string {
+	if c.FrameOptions == "" {
+		return "DENY"
+	}
+	return c.FrameOptions
+}
+
+// GetReferrerPolicy 获取 Referrer-Policy（默认 strict-origin-when-cross-origin）
+func (c *SecurityHeadersConfig) GetReferrerPolicy() string {
+	if c.ReferrerPolicy == "" {
+		return "strict-origin-when-cross-origin"
+	}
+	return c.ReferrerPolicy
+}
+
+// 链路追踪导出器常量
+const (
+	TracingExporterOTLP   = "otlp"   // OTLP HTTP
+	TracingExporterStdout = "stdout" // 输出到标准输出
+	TracingExporterFile   = "file"   // 输出到文件
+)
+
+// GetExporter 获取链路追踪导出器（默认 otlp）
+func (c *TracingConfig) GetExporter() string {
+	if c.Exporter == "" {
+		return TracingExporterOTLP
+	}
+	return c.Exporter
+}
+
+// GetServiceName 获取服务名（默认 task-management-system）
+func (c *TracingConfig) GetServiceName() string {
+	if c.ServiceName == "" {
+		return "task-management-system"
+	}
+	return c.ServiceName
+}
+
+// GetSampleRatio 获取采样比例（未配置或超出范围时全部采样）
+func (c *TracingConfig) GetSampleRatio() float64 {
+	if c.SampleRatio <= 0 || c.SampleRatio > 1 {
+		return 1
+	}
+	return c.SampleRatio
+}
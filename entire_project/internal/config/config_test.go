@@ -0,0 +1,166 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validConfig 返回一份可以通过校验的配置
+func validConfig() *Config {
+	return &Config{
+		Server: ServerConfig{Port: 8080, Mode: "debug", RequestTimeout: 10, WriteTimeout: 15},
+		Database: DatabaseConfig{MySQL: MySQLConfig{
+			Host: "localhost", Port: 3306, Username: "root", DBName: "task_management",
+			Charset: "utf8mb4", ParseTime: true, Loc: "Local", MaxIdleConns: 10, MaxOpenConns: 100,
+		}},
+		Redis: RedisConfig{Host: "localhost", Port: 6379, PoolSize: 10, MinIdleConns: 5},
+		Log:   LogConfig{Level: "info", Format: "json"},
+		Auth:  AuthConfig{Secret: DefaultAuthSecret, AccessTokenTTL: 900, RefreshTokenTTL: 604800},
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		modify     func(cfg *Config)
+		wantFields []string
+	}{
+		{"有效配置", func(cfg *Config) {}, nil},
+		{"端口超出范围", func(cfg *Config) { cfg.Server.Port = 70000 }, []string{"server.port"}},
+		{"未知运行模式", func(cfg *Config) { cfg.Server.Mode = "prod" }, []string{"server.mode"}},
+		{"写超时不大于请求超时", func(cfg *Config) { cfg.Server.WriteTimeout = 10 }, []string{"server.write_timeout"}},
+		{"空闲连接数大于最大连接数", func(cfg *Config) { cfg.Database.MySQL.MaxIdleConns = 200 },
+			[]string{"database.mysql.max_idle_conns"}},
+		{"DSN 时区不合法", func(cfg *Config) { cfg.Database.MySQL.Loc = "Mars/Olympus" }, []string{"database.mysql"}},
+		{"Redis 连接池为负数", func(cfg *Config) { cfg.Redis.PoolSize = -1 }, []string{"redis.pool_size"}},
+		{"内存缓存不校验 Redis 地址", func(cfg *Config) {
+			cfg.Redis = RedisConfig{Driver: CacheDriverMemory}
+		}, nil},
+		{"无效日志级别", func(cfg *Config) { cfg.Log.Level = "verbose" }, []string{"log.level"}},
+		{"release 模式使用示例密钥", func(cfg *Config) { cfg.Server.Mode = "release" }, []string{"auth.secret", "auth.secret"}},
+		{"启用 otlp 但未配置地址", func(cfg *Config) { cfg.Tracing = TracingConfig{Enabled: true} }, []string{"tracing.endpoint"}},
+		{"允许认证信息时使用通配来源", func(cfg *Config) {
+			cfg.CORS = CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true}
+		}, []string{"cors.allowed_origins"}},
+		{"来源格式不合法", func(cfg *Config) {
+			cfg.CORS.AllowedOrigins = []string{"https://app.example.com", "app.example.com", "https://a.*.com"}
+			cfg.CORS.Routes = []CORSRouteConfig{{PathPrefix: "public"}}
+		}, []string{"cors.allowed_origins", "cors.allowed_origins", "cors.routes[0].path_prefix"}},
+		{"子域名通配来源", func(cfg *Config) {
+			cfg.CORS = CORSConfig{AllowedOrigins: []string{"https://*.example.com", "http://localhost:3000"}, AllowCredentials: true}
+		}, nil},
+		{"多个错误一次报告", func(cfg *Config) {
+			cfg.Server.Port = 0
+			cfg.Database.MySQL.Host = ""
+		}, []string{"server.port", "database.mysql.host"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if tt.wantFields == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var verr *ValidationError
+			require.True(t, errors.As(err, &verr), "应返回 ValidationError")
+			var fields []string
+			for _, fieldErr := range verr.Errors {
+				fields = append(fields, fieldErr.Field)
+			}
+			assert.Equal(t, tt.wantFields, fields)
+		})
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	configPath := writeFile("config.yaml", `
+server:
+  port: 8080
+  mode: debug
+database:
+  mysql:
+    host: localhost
+    port: 3306
+    username: root
+    password: base
+    dbname: task_management
+    charset: utf8mb4
+    loc: Local
+redis:
+  driver: memory
+log:
+  level: debug
+  file_path: ./logs/app.log
+auth:
+  secret: change-me-in-production
+`)
+	writeFile("config.prod.yaml", `
+log:
+  level: info
+`)
+	secretPath := writeFile("jwt_secret", "from-secret-file\n")
+
+	t.Setenv("TASK_SERVER_PORT", "9090")
+	t.Setenv("TASK_DATABASE_MYSQL_PASSWORD", "from-env")
+	t.Setenv("TASK_CACHE_STATS_TTL", "5")
+	t.Setenv("TASK_AUTH_SECRET_FILE", secretPath)
+
+	cfg, err := LoadProfile(configPath, "prod")
+	require.NoError(t, err)
+
+	assert.Equal(t, "info", cfg.Log.Level, "环境配置文件覆盖基础配置")
+	assert.Equal(t, "debug", cfg.Server.Mode, "环境配置文件未写出的字段沿用基础配置")
+	assert.Equal(t, "./logs/app.log", cfg.Log.FilePath, "多单词字段按 mapstructure 标签解析")
+	assert.Equal(t, 9090, cfg.Server.Port, "环境变量覆盖配置文件")
+	assert.Equal(t, "from-env", cfg.Database.MySQL.Password, "嵌套字段的环境变量")
+	assert.Equal(t, 5, cfg.Cache.StatsTTL, "配置文件中没有的字段也能通过环境变量设置")
+	assert.Equal(t, "from-secret-file", cfg.Auth.Secret, "密钥文件覆盖配置并去掉末尾换行")
+
+	t.Setenv("TASK_SERVER_PORT", "0")
+	_, err = LoadProfile(configPath, "")
+	var verr *ValidationError
+	assert.True(t, errors.As(err, &verr), "加载后执行校验")
+}
+
+func TestConfig_Dump(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database.MySQL.Password = "db-password"
+
+	var buf bytes.Buffer
+	require.NoError(t, cfg.Dump(&buf))
+
+	assert.NotContains(t, buf.String(), "db-password")
+	assert.NotContains(t, buf.String(), DefaultAuthSecret)
+	assert.Contains(t, buf.String(), redactedValue)
+	assert.Equal(t, "db-password", cfg.Database.MySQL.Password, "脱敏不修改原配置")
+}
+
+func TestRequiresRestart(t *testing.T) {
+	old := validConfig()
+
+	next := validConfig()
+	next.Log.Level = "debug"
+	next.Cache.StatsTTL = 5
+	assert.False(t, RequiresRestart(old, next), "日志级别和缓存有效期可以热更新")
+
+	next.Server.Port = 9090
+	assert.True(t, RequiresRestart(old, next))
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redactedValue 脱敏后的占位符
+const redactedValue = "******"
+
+// Redacted 返回隐藏了密码和密钥的配置副本
+// 学习要点：返回副本而不是修改原配置；空值保持为空，便于看出密钥是否已配置
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	for _, secret := range []*string{
+		&redacted.Database.MySQL.Password,
+		&redacted.Redis.Password,
+		&redacted.Auth.Secret,
+	} {
+		if *secret != "" {
+			*secret = redactedValue
+		}
+	}
+	return &redacted
+}
+
+// Dump 以 YAML 格式输出合并后的配置，密钥已脱敏
+func (c *Config) Dump(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c.Redacted()); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
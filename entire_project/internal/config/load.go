@@ -0,0 +1,137 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// 环境变量
+const (
+	EnvPrefix  = "TASK"         // 环境变量前缀，如 TASK_DATABASE_MYSQL_PASSWORD
+	ProfileEnv = "TASK_PROFILE" // 运行环境，如 dev、prod，对应 config.<profile>.yaml
+)
+
+// Load 加载配置文件，运行环境取自 TASK_PROFILE
+// 学习要点：返回配置实例而不是写入全局变量，由调用方显式传递给需要的组件
+func Load(configPath string) (*Config, error) {
+	return LoadProfile(configPath, os.Getenv(ProfileEnv))
+}
+
+// LoadProfile 按指定运行环境加载配置
+// 学习要点：加载流程依次为读取基础文件、合并环境文件、绑定环境变量、读取密钥文件、校验，
+// 任何一步失败都拒绝启动，而不是带着错误的配置运行
+func LoadProfile(configPath, profile string) (*Config, error) {
+	v := viper.New()
+	v.SetConfigFile(configPath)
+	v.SetConfigType("yaml")
+
+	// 读取基础配置文件
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	// 合并环境配置文件：只需写出与基础配置不同的字段
+	if profilePath := ProfilePath(configPath, profile); profilePath != "" {
+		v.SetConfigFile(profilePath)
+		if err := v.MergeInConfig(); err != nil {
+			return nil, fmt.Errorf("读取环境配置文件失败: %w", err)
+		}
+	}
+
+	// 绑定环境变量
+	// 学习要点：AutomaticEnv 只对 viper 已知的键生效，配置文件中没有写出的字段
+	// 必须显式 BindEnv，Unmarshal 时才能读到对应的环境变量
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	for _, key := range configKeys(reflect.TypeOf(Config{}), "") {
+		if err := v.BindEnv(key); err != nil {
+			return nil, fmt.Errorf("绑定环境变量失败: %w", err)
+		}
+	}
+
+	// 解析配置到结构体
+	cfg := &Config{}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, fmt.Errorf("解析配置文件失败: %w", err)
+	}
+
+	if err := cfg.loadSecretFiles(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ProfilePath 获取环境配置文件路径，如 configs/config.yaml + prod 得到 configs/config.prod.yaml；
+// 未指定运行环境时返回空字符串
+func ProfilePath(configPath, profile string) string {
+	if profile == "" {
+		return ""
+	}
+	ext := filepath.Ext(configPath)
+	return strings.TrimSuffix(configPath, ext) + "." + profile + ext
+}
+
+// EnvName 获取配置键对应的环境变量名，如 database.mysql.password 对应 TASK_DATABASE_MYSQL_PASSWORD
+func EnvName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// configKeys 递归收集结构体所有叶子字段的配置键
+func configKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("mapstructure")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		key := tag
+		if prefix != "" {
+			key = prefix + "." + tag
+		}
+		if field.Type.Kind() == reflect.Struct {
+			keys = append(keys, configKeys(field.Type, key)...)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// loadSecretFiles 从文件读取密钥
+// 学习要点：Docker/k8s 的 Secret 以文件形式挂载，避免密码出现在环境变量和进程列表中
+func (c *Config) loadSecretFiles() error {
+	secrets := []struct {
+		path   string
+		target *string
+	}{
+		{c.Database.MySQL.PasswordFile, &c.Database.MySQL.Password},
+		{c.Redis.PasswordFile, &c.Redis.Password},
+		{c.Auth.SecretFile, &c.Auth.Secret},
+	}
+
+	var errs []error
+	for _, secret := range secrets {
+		if secret.path == "" {
+			continue
+		}
+		data, err := os.ReadFile(secret.path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("读取密钥文件失败: %w", err))
+			continue
+		}
+		// 文件末尾的换行通常是编辑器或 echo 添加的，不属于密钥
+		*secret.target = strings.TrimRight(string(data), "\r\n")
+	}
+	return errors.Join(errs...)
+}
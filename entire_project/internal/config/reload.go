@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Live 运行时可热更新的配置
+// 学习要点：热更新的字段通过原子指针读写，请求处理中读取无需加锁；
+// 其余字段（端口、连接池等）在启动时已用于创建连接，修改后需要重启才能生效
+type Live struct {
+	cache atomic.Pointer[CacheConfig]
+}
+
+// NewLive 使用启动时的配置创建可热更新配置
+func NewLive(cfg *Config) *Live {
+	live := &Live{}
+	live.Store(cfg)
+	return live
+}
+
+// Cache 获取当前的缓存有效期配置；Live 为 nil 时返回默认值
+func (l *Live) Cache() *CacheConfig {
+	if l == nil {
+		return &CacheConfig{}
+	}
+	return l.cache.Load()
+}
+
+// Store 应用新配置中可热更新的字段
+func (l *Live) Store(cfg *Config) {
+	cacheCfg := cfg.Cache
+	l.cache.Store(&cacheCfg)
+}
+
+// RequiresRestart 判断两份配置在可热更新字段之外是否有差异
+func RequiresRestart(old, next *Config) bool {
+	a, b := *old, *next
+	a.Log.Level, b.Log.Level = "", ""
+	a.Cache, b.Cache = CacheConfig{}, CacheConfig{}
+	return !reflect.DeepEqual(a, b)
+}
+
+// Watch 监听基础配置文件和环境配置文件，变化后重新加载并回调 onChange，返回停止监听的函数
+// 学习要点：文件变化后重新执行完整的加载流程（合并、环境变量、密钥文件、校验），
+// 校验失败时保留旧配置并记录错误，一次错误的编辑不会影响正在运行的服务。
+// 监听所在目录而不是文件本身：编辑器保存和 k8s ConfigMap 更新都会替换文件（符号链接），
+// 直接监听文件会在第一次替换后失效。优雅关闭时先调用 stop，stop 返回后不会再回调 onChange
+func Watch(configPath string, onChange func(*Config)) (stop func(), err error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("创建配置文件监听失败: %w", err)
+	}
+
+	// 被监听文件 -> 符号链接解析后的真实路径，真实路径变化说明文件被整体替换
+	files := make(map[string]string)
+	for _, path := range []string{configPath, ProfilePath(configPath, os.Getenv(ProfileEnv))} {
+		if path == "" {
+			continue
+		}
+		path = filepath.Clean(path)
+		files[path], _ = filepath.EvalSymlinks(path)
+		if err := watcher.Add(filepath.Dir(path)); err != nil {
+			watcher.Close()
+			return nil, fmt.Errorf("监听配置目录失败: %w", err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if configChanged(files, event) {
+					reloadConfig(configPath, event.Name, onChange)
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				slog.Error("配置文件监听出错", "error", err)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			watcher.Close()
+			<-done
+		})
+	}, nil
+}
+
+// configChanged 判断事件是否修改了被监听的配置文件，并更新记录的真实路径
+func configChanged(files map[string]string, event fsnotify.Event) bool {
+	changed := false
+	for path, realPath := range files {
+		current, _ := filepath.EvalSymlinks(path)
+		written := filepath.Clean(event.Name) == path && (event.Has(fsnotify.Write) || event.Has(fsnotify.Create))
+		if written || (current != "" && current != realPath) {
+			files[path] = current
+			changed = true
+		}
+	}
+	return changed
+}
+
+// reloadConfig 重新加载配置，失败时保留旧配置
+func reloadConfig(configPath, file string, onChange func(*Config)) {
+	cfg, err := Load(configPath)
+	if err != nil {
+		slog.Error("配置热更新失败，继续使用旧配置", "file", file, "error", err)
+		return
+	}
+	onChange(cfg)
+}
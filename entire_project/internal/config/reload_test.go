@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// watchConfig 返回指定日志级别的最小可用配置文件内容
+func watchConfig(level string) string {
+	return `
+server:
+  port: 8080
+  mode: debug
+database:
+  mysql:
+    host: localhost
+    port: 3306
+    username: root
+    dbname: task_management
+    charset: utf8mb4
+    loc: Local
+redis:
+  driver: memory
+log:
+  level: ` + level + `
+auth:
+  secret: change-me-in-production
+`
+}
+
+func TestWatch_StopEndsReload(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte(watchConfig("debug")), 0o600))
+
+	changes := make(chan *Config, 10)
+	stop, err := Watch(configPath, func(next *Config) {
+		changes <- next
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(configPath, []byte(watchConfig("info")), 0o600))
+	select {
+	case next := <-changes:
+		assert.Equal(t, "info", next.Log.Level, "修改配置文件后回调收到新配置")
+	case <-time.After(5 * time.Second):
+		t.Fatal("修改配置文件后没有触发热更新")
+	}
+
+	// stop 返回后监听协程已退出，之后的修改不再触发回调
+	stop()
+	stop() // 重复调用是安全的
+	for len(changes) > 0 {
+		<-changes
+	}
+	require.NoError(t, os.WriteFile(configPath, []byte(watchConfig("warn")), 0o600))
+	select {
+	case next := <-changes:
+		t.Fatalf("停止监听后仍触发热更新: %s", next.Log.Level)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
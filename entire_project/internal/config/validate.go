@@ -0,0 +1,256 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// DefaultAuthSecret 示例配置中的JWT签名密钥，release 模式下禁止使用
+const DefaultAuthSecret = "change-me-in-production"
+
+// minReleaseSecretLength release 模式下JWT签名密钥的最小长度
+const minReleaseSecretLength = 32
+
+// FieldError 单个配置项的校验错误
+type FieldError struct {
+	Field   string // 配置键，如 server.port
+	Message string // 错误原因
+}
+
+// Error 实现 error 接口
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError 配置校验错误，包含所有不合法的配置项
+// 学习要点：一次性报告全部错误，而不是改一个错、启动一次、再看到下一个错
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+// Error 实现 error 接口
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return "配置校验失败: " + strings.Join(messages, "; ")
+}
+
+// validator 收集校验错误
+type validator struct {
+	errs []*FieldError
+}
+
+// check 条件不成立时记录错误
+func (v *validator) check(ok bool, field, format string, args ...any) {
+	if !ok {
+		v.errs = append(v.errs, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+// port 校验端口范围
+func (v *validator) port(field string, port int) {
+	v.check(port > 0 && port <= 65535, field, "端口必须在 1-65535 之间，当前为 %d", port)
+}
+
+// nonNegative 校验非负数
+func (v *validator) nonNegative(field string, value int) {
+	v.check(value >= 0, field, "不能为负数，当前为 %d", value)
+}
+
+// oneOf 校验枚举值，空值视为使用默认值
+func (v *validator) oneOf(field, value string, allowed ...string) {
+	if value == "" {
+		return
+	}
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.check(false, field, "必须是 %s 之一，当前为 %q", strings.Join(allowed, "、"), value)
+}
+
+// Validate 校验配置
+// 学习要点：启动时校验，把配置错误变成明确的启动失败，而不是运行中才出现的连接错误
+func (c *Config) Validate() error {
+	v := &validator{}
+	c.Server.validate(v)
+	c.Database.MySQL.validate(v)
+	c.Redis.validate(v)
+	c.Cache.validate(v)
+	c.Log.validate(v)
+	c.Auth.validate(v, c.Server.Mode == "release")
+	c.Tracing.validate(v)
+	c.RateLimit.validate(v)
+	c.CORS.validate(v)
+	c.SecurityHeaders.validate(v)
+
+	if len(v.errs) > 0 {
+		return &ValidationError{Errors: v.errs}
+	}
+	return nil
+}
+
+// validate 校验服务器配置
+func (c *ServerConfig) validate(v *validator) {
+	v.port("server.port", c.Port)
+	v.oneOf("server.mode", c.Mode, "debug", "release", "test")
+	v.nonNegative("server.request_timeout", c.RequestTimeout)
+	v.nonNegative("server.read_timeout", c.ReadTimeout)
+	v.nonNegative("server.write_timeout", c.WriteTimeout)
+	v.nonNegative("server.idle_timeout", c.IdleTimeout)
+	v.nonNegative("server.shutdown_timeout", c.ShutdownTimeout)
+	v.nonNegative("server.drain_delay", c.DrainDelay)
+	if c.WriteTimeout > 0 {
+		v.check(c.GetWriteTimeout() > c.GetRequestTimeout(), "server.write_timeout",
+			"必须大于 request_timeout(%s)，否则超时响应无法写出", c.GetRequestTimeout())
+	}
+}
+
+// validate 校验MySQL配置
+func (c *MySQLConfig) validate(v *validator) {
+	v.check(c.Host != "", "database.mysql.host", "不能为空")
+	v.port("database.mysql.port", c.Port)
+	v.check(c.Username != "", "database.mysql.username", "不能为空")
+	v.check(c.DBName != "", "database.mysql.dbname", "不能为空")
+	v.nonNegative("database.mysql.max_idle_conns", c.MaxIdleConns)
+	v.nonNegative("database.mysql.max_open_conns", c.MaxOpenConns)
+	v.nonNegative("database.mysql.conn_max_lifetime", c.ConnMaxLifetime)
+	if c.MaxOpenConns > 0 {
+		v.check(c.MaxIdleConns <= c.MaxOpenConns, "database.mysql.max_idle_conns",
+			"不能大于 max_open_conns(%d)", c.MaxOpenConns)
+	}
+
+	// 用驱动自己的解析函数检查 DSN，字符集、时区等参数错误在启动时就能发现
+	if _, err := mysql.ParseDSN(c.GetMySQLDSN()); err != nil {
+		v.check(false, "database.mysql", "DSN 不合法: %v", err)
+	}
+}
+
+// validate 校验Redis配置
+func (c *RedisConfig) validate(v *validator) {
+	v.oneOf("redis.driver", c.Driver, CacheDriverRedis, CacheDriverMemory)
+	v.nonNegative("redis.memory_max_entries", c.MemoryMaxEntries)
+	if c.GetDriver() != CacheDriverRedis {
+		return
+	}
+	v.check(c.Host != "", "redis.host", "不能为空")
+	v.port("redis.port", c.Port)
+	v.check(c.DB >= 0 && c.DB <= 15, "redis.db", "必须在 0-15 之间，当前为 %d", c.DB)
+	v.nonNegative("redis.pool_size", c.PoolSize)
+	v.nonNegative("redis.min_idle_conns", c.MinIdleConns)
+	if c.PoolSize > 0 {
+		v.check(c.MinIdleConns <= c.PoolSize, "redis.min_idle_conns", "不能大于 pool_size(%d)", c.PoolSize)
+	}
+}
+
+// validate 校验缓存有效期配置
+func (c *CacheConfig) validate(v *validator) {
+	v.nonNegative("cache.task_ttl", c.TaskTTL)
+	v.nonNegative("cache.task_count_ttl", c.TaskCountTTL)
+	v.nonNegative("cache.user_ttl", c.UserTTL)
+	v.nonNegative("cache.user_search_ttl", c.UserSearchTTL)
+	v.nonNegative("cache.active_users_ttl", c.ActiveUsersTTL)
+	v.nonNegative("cache.stats_ttl", c.StatsTTL)
+}
+
+// validate 校验日志配置
+func (c *LogConfig) validate(v *validator) {
+	if c.Level != "" {
+		var level slog.Level
+		v.check(level.UnmarshalText([]byte(c.Level)) == nil, "log.level",
+			"必须是 debug、info、warn、error 之一，当前为 %q", c.Level)
+	}
+	v.oneOf("log.format", strings.ToLower(c.Format), "json", "console")
+	v.nonNegative("log.max_size", c.MaxSize)
+	v.nonNegative("log.max_backups", c.MaxBackups)
+	v.nonNegative("log.max_age", c.MaxAge)
+}
+
+// validate 校验认证配置
+// 学习要点：debug 和 test 模式允许使用示例密钥，release 模式下必须替换为足够长的随机密钥
+func (c *AuthConfig) validate(v *validator, releaseMode bool) {
+	v.check(c.Secret != "", "auth.secret", "不能为空")
+	if releaseMode && c.Secret != "" {
+		v.check(c.Secret != DefaultAuthSecret, "auth.secret", "release 模式下不能使用示例密钥")
+		v.check(len(c.Secret) >= minReleaseSecretLength, "auth.secret",
+			"release 模式下长度不能少于 %d 个字符", minReleaseSecretLength)
+	}
+	v.nonNegative("auth.access_token_ttl", c.AccessTokenTTL)
+	v.nonNegative("auth.refresh_token_ttl", c.RefreshTokenTTL)
+	v.nonNegative("auth.max_login_attempts", c.MaxLoginAttempts)
+	v.nonNegative("auth.max_login_attempts_per_ip", c.MaxLoginAttemptsPerIP)
+	v.nonNegative("auth.login_attempt_window", c.LoginAttemptWindow)
+	v.nonNegative("auth.lockout_duration", c.LockoutDuration)
+	v.nonNegative("auth.max_lockout_duration", c.MaxLockoutDuration)
+	if c.AccessTokenTTL > 0 && c.RefreshTokenTTL > 0 {
+		v.check(c.RefreshTokenTTL > c.AccessTokenTTL, "auth.refresh_token_ttl",
+			"必须大于 access_token_ttl(%d)", c.AccessTokenTTL)
+	}
+}
+
+// validate 校验链路追踪配置，未启用时不校验
+func (c *TracingConfig) validate(v *validator) {
+	if !c.Enabled {
+		return
+	}
+	v.oneOf("tracing.exporter", c.Exporter, TracingExporterOTLP, TracingExporterStdout, TracingExporterFile)
+	v.check(c.SampleRatio >= 0 && c.SampleRatio <= 1, "tracing.sample_ratio",
+		"必须在 0-1 之间，当前为 %g", c.SampleRatio)
+	switch c.GetExporter() {
+	case TracingExporterOTLP:
+		v.check(c.Endpoint != "", "tracing.endpoint", "exporter 为 otlp 时不能为空")
+	case TracingExporterFile:
+		v.check(c.FilePath != "", "tracing.file_path", "exporter 为 file 时不能为空")
+	}
+}
+
+// validate 校验限流配置，0 表示使用默认值
+func (c *RateLimitConfig) validate(v *validator) {
+	rules := []struct {
+		name string
+		rule RateLimitRule
+	}{{"user", c.User}, {"task", c.Task}, {"admin", c.Admin}}
+	for _, r := range rules {
+		v.nonNegative("rate_limit."+r.name+".requests", r.rule.Requests)
+		v.nonNegative("rate_limit."+r.name+".window", r.rule.Window)
+	}
+}
+
+// validate 校验跨域配置
+func (c *CORSConfig) validate(v *validator) {
+	validateOrigins(v, "cors.allowed_origins", c.AllowedOrigins, c.AllowCredentials)
+	for i, route := range c.Routes {
+		field := fmt.Sprintf("cors.routes[%d]", i)
+		v.check(strings.HasPrefix(route.PathPrefix, "/"), field+".path_prefix", "必须以 / 开头，当前为 %q", route.PathPrefix)
+		validateOrigins(v, field+".allowed_origins", route.AllowedOrigins, route.AllowCredentials)
+	}
+}
+
+// validateOrigins 校验来源列表：* 或 scheme://host[:port]，host 可以以 *. 开头匹配子域名
+// 学习要点：浏览器拒绝同时返回 Allow-Origin: * 和 Allow-Credentials: true，启动时就报错
+func validateOrigins(v *validator, field string, origins []string, allowCredentials bool) {
+	for _, origin := range origins {
+		if origin == "*" {
+			v.check(!allowCredentials, field, "允许携带认证信息时不能使用 *，请列出具体来源")
+			continue
+		}
+		u, err := url.Parse(strings.Replace(origin, "*.", "wildcard.", 1))
+		ok := err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" &&
+			u.Path == "" && u.RawQuery == "" && u.User == nil
+		ok = ok && (!strings.Contains(origin, "*") || strings.HasPrefix(origin, u.Scheme+"://*."))
+		v.check(ok, field, "来源格式必须是 scheme://host[:port] 或 scheme://*.domain，当前为 %q", origin)
+	}
+}
+
+// validate 校验安全响应头配置
+func (c *SecurityHeadersConfig) validate(v *validator) {
+	v.nonNegative("security_headers.hsts_max_age", c.HSTSMaxAge)
+	v.oneOf("security_headers.frame_options", strings.ToUpper(c.FrameOptions), "DENY", "SAMEORIGIN")
+}
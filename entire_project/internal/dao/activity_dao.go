@@ -0,0 +1,62 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"task-management-system/internal/models"
+)
+
+// ActivityDAO 任务动态数据访问接口
+// 学习要点：操作日志只追加，因此接口中没有更新和删除方法
+type ActivityDAO interface {
+	Create(ctx context.Context, activities []models.TaskActivity) error
+
+	// ListByTaskID 查询任务的动态，beforeID 为 0 表示从最新一条开始
+	ListByTaskID(ctx context.Context, taskID, beforeID uint, limit int) ([]models.TaskActivity, error)
+
+	// 事务支持
+	WithTx(tx *gorm.DB) ActivityDAO
+}
+
+// activityDAO 任务动态DAO实现
+type activityDAO struct {
+	db *gorm.DB
+}
+
+// NewActivityDAO 创建任务动态DAO实例
+func NewActivityDAO(db *gorm.DB) ActivityDAO {
+	return &activityDAO{db: db}
+}
+
+// Create 批量写入动态
+func (d *activityDAO) Create(ctx context.Context, activities []models.TaskActivity) error {
+	if len(activities) == 0 {
+		return nil
+	}
+	if err := d.db.WithContext(ctx).Create(&activities).Error; err != nil {
+		return fmt.Errorf("记录任务动态失败: %w", err)
+	}
+	return nil
+}
+
+// ListByTaskID 游标分页查询任务的动态
+func (d *activityDAO) ListByTaskID(ctx context.Context, taskID, beforeID uint, limit int) ([]models.TaskActivity, error) {
+	var activities []models.TaskActivity
+
+	query := d.db.WithContext(ctx).Where("task_id = ?", taskID)
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+	if err := query.Order("id DESC").Limit(limit).Find(&activities).Error; err != nil {
+		return nil, fmt.Errorf("查询任务动态失败: %w", err)
+	}
+
+	return activities, nil
+}
+
+// WithTx 返回绑定事务的DAO
+func (d *activityDAO) WithTx(tx *gorm.DB) ActivityDAO {
+	return &activityDAO{db: tx}
+}
@@ -0,0 +1,103 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/models"
+)
+
+// ErrCommentNotFound 评论不存在
+var ErrCommentNotFound = apperr.NotFound("comment_not_found", "评论不存在")
+
+// CommentDAO 任务评论数据访问接口
+// 学习要点：列表查询使用游标分页，按ID倒序返回最新的评论
+type CommentDAO interface {
+	Create(ctx context.Context, comment *models.TaskComment) error
+	GetByID(ctx context.Context, id uint) (*models.TaskComment, error)
+	UpdateBody(ctx context.Context, id uint, body string) error
+	Delete(ctx context.Context, id uint) error
+
+	// ListByTaskID 查询任务的评论，beforeID 为 0 表示从最新一条开始
+	ListByTaskID(ctx context.Context, taskID, beforeID uint, limit int) ([]models.TaskComment, error)
+
+	// 事务支持
+	WithTx(tx *gorm.DB) CommentDAO
+}
+
+// commentDAO 任务评论DAO实现
+type commentDAO struct {
+	db *gorm.DB
+}
+
+// NewCommentDAO 创建任务评论DAO实例
+func NewCommentDAO(db *gorm.DB) CommentDAO {
+	return &commentDAO{db: db}
+}
+
+// Create 创建评论
+func (d *commentDAO) Create(ctx context.Context, comment *models.TaskComment) error {
+	if err := d.db.WithContext(ctx).Create(comment).Error; err != nil {
+		return fmt.Errorf("创建评论失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取评论（包含作者信息）
+func (d *commentDAO) GetByID(ctx context.Context, id uint) (*models.TaskComment, error) {
+	var comment models.TaskComment
+	err := d.db.WithContext(ctx).Preload("User").First(&comment, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: ID=%d", ErrCommentNotFound, id)
+		}
+		return nil, fmt.Errorf("查询评论失败: %w", err)
+	}
+	return &comment, nil
+}
+
+// UpdateBody 修改评论内容
+func (d *commentDAO) UpdateBody(ctx context.Context, id uint, body string) error {
+	if err := d.db.WithContext(ctx).Model(&models.TaskComment{}).
+		Where("id = ?", id).
+		Update("body", body).Error; err != nil {
+		return fmt.Errorf("更新评论失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除评论（软删除）
+func (d *commentDAO) Delete(ctx context.Context, id uint) error {
+	if err := d.db.WithContext(ctx).Delete(&models.TaskComment{}, id).Error; err != nil {
+		return fmt.Errorf("删除评论失败: %w", err)
+	}
+	return nil
+}
+
+// ListByTaskID 游标分页查询任务的评论
+// 学习要点：WHERE id < 游标 ORDER BY id DESC 利用索引直接定位，翻到多深都只扫描 limit 行
+func (d *commentDAO) ListByTaskID(ctx context.Context, taskID, beforeID uint, limit int) ([]models.TaskComment, error) {
+	var comments []models.TaskComment
+
+	query := d.db.WithContext(ctx).Where("task_id = ?", taskID)
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+	if err := query.
+		Preload("User").
+		Order("id DESC").
+		Limit(limit).
+		Find(&comments).Error; err != nil {
+		return nil, fmt.Errorf("查询评论列表失败: %w", err)
+	}
+
+	return comments, nil
+}
+
+// WithTx 返回绑定事务的DAO
+func (d *commentDAO) WithTx(tx *gorm.DB) CommentDAO {
+	return &commentDAO{db: tx}
+}
@@ -0,0 +1,18 @@
+package dao
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlErrDuplicateEntry MySQL 唯一索引冲突的错误码
+const mysqlErrDuplicateEntry = 1062
+
+// isDuplicateKeyError 判断是否为唯一索引冲突
+// 学习要点：服务层的"先查询再插入"挡不住并发请求，唯一性最终由数据库索引保证，
+// DAO 把索引冲突转换为业务错误，而不是当作内部错误返回
+func isDuplicateKeyError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
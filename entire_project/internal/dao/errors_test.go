@@ -0,0 +1,29 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsDuplicateKeyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"唯一索引冲突", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}, true},
+		{"包装后的唯一索引冲突", fmt.Errorf("创建标签失败: %w", &mysql.MySQLError{Number: 1062}), true},
+		{"其他MySQL错误", &mysql.MySQLError{Number: 1452}, false},
+		{"普通错误", errors.New("connection refused"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isDuplicateKeyError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/models"
+)
+
+// 项目相关错误
+var (
+	ErrProjectNotFound       = apperr.NotFound("project_not_found", "项目不存在")
+	ErrProjectMemberNotFound = apperr.NotFound("project_member_not_found", "用户不是项目成员")
+)
+
+// ProjectDAO 项目数据访问接口
+// 学习要点：项目和项目成员关系紧密，放在同一个DAO中维护
+type ProjectDAO interface {
+	// 基础CRUD操作
+	Create(ctx context.Context, project *models.Project) error
+	GetByID(ctx context.Context, id uint) (*models.Project, error)
+	UpdateFields(ctx context.Context, id uint, updates map[string]interface{}) error
+	Delete(ctx context.Context, id uint) error
+
+	// 查询操作
+	List(ctx context.Context, offset, limit int) ([]models.Project, int64, error)
+	ListByMember(ctx context.Context, userID uint, offset, limit int) ([]models.Project, int64, error)
+	CountTasks(ctx context.Context, projectID uint) (int64, error)
+
+	// 成员操作
+	GetMember(ctx context.Context, projectID, userID uint) (*models.ProjectMember, error)
+	ListMembers(ctx context.Context, projectID uint) ([]models.ProjectMember, error)
+	AddMember(ctx context.Context, member *models.ProjectMember) error
+	UpdateMemberRole(ctx context.Context, projectID, userID uint, role string) error
+	RemoveMember(ctx context.Context, projectID, userID uint) error
+	RemoveAllMembers(ctx context.Context, projectID uint) error
+
+	// 事务支持
+	WithTx(tx *gorm.DB) ProjectDAO
+}
+
+// projectDAO 项目DAO实现
+type projectDAO struct {
+	db *gorm.DB
+}
+
+// NewProjectDAO 创建项目DAO实例
+func NewProjectDAO(db *gorm.DB) ProjectDAO {
+	return &projectDAO{db: db}
+}
+
+// Create 创建项目
+func (d *projectDAO) Create(ctx context.Context, project *models.Project) error {
+	if err := d.db.WithContext(ctx).Omit("Members").Create(project).Error; err != nil {
+		return fmt.Errorf("创建项目失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取项目（包含所有者信息）
+func (d *projectDAO) GetByID(ctx context.Context, id uint) (*models.Project, error) {
+	var project models.Project
+	err := d.db.WithContext(ctx).Preload("Owner").First(&project, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: ID=%d", ErrProjectNotFound, id)
+		}
+		return nil, fmt.Errorf("查询项目失败: %w", err)
+	}
+	return &project, nil
+}
+
+// UpdateFields 部分更新项目字段
+func (d *projectDAO) UpdateFields(ctx context.Context, id uint, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	if err := d.db.WithContext(ctx).Model(&models.Project{}).
+		Where("id = ?", id).
+		Updates(updates).Error; err != nil {
+		return fmt.Errorf("更新项目失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除项目（软删除）
+func (d *projectDAO) Delete(ctx context.Context, id uint) error {
+	if err := d.db.WithContext(ctx).Delete(&models.Project{}, id).Error; err != nil {
+		return fmt.Errorf("删除项目失败: %w", err)
+	}
+	return nil
+}
+
+// List 获取所有项目（管理员使用）
+func (d *projectDAO) List(ctx context.Context, offset, limit int) ([]models.Project, int64, error) {
+	return d.list(d.db.WithContext(ctx).Model(&models.Project{}), offset, limit)
+}
+
+// ListByMember 获取用户参与的项目
+// 学习要点：通过子查询按成员表过滤，不需要 JOIN 后再去重
+func (d *projectDAO) ListByMember(ctx context.Context, userID uint, offset, limit int) ([]models.Project, int64, error) {
+	query := d.db.WithContext(ctx).Model(&models.Project{}).
+		Where("id IN (SELECT project_id FROM project_members WHERE user_id = ?)", userID)
+	return d.list(query, offset, limit)
+}
+
+// list 分页查询项目列表
+func (d *projectDAO) list(query *gorm.DB, offset, limit int) ([]models.Project, int64, error) {
+	var projects []models.Project
+	var total int64
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计项目总数失败: %w", err)
+	}
+
+	if err := query.
+		Preload("Owner").
+		Order("id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&projects).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询项目列表失败: %w", err)
+	}
+
+	return projects, total, nil
+}
+
+// CountTasks 统计项目中未删除的任务数
+func (d *projectDAO) CountTasks(ctx context.Context, projectID uint) (int64, error) {
+	var count int64
+	if err := d.db.WithContext(ctx).Model(&models.Task{}).
+		Where("project_id = ?", projectID).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("统计项目任务数失败: %w", err)
+	}
+	return count, nil
+}
+
+// GetMember 获取用户在项目中的成员信息
+func (d *projectDAO) GetMember(ctx context.Context, projectID, userID uint) (*models.ProjectMember, error) {
+	var member models.ProjectMember
+	err := d.db.WithContext(ctx).
+		Where("project_id = ? AND user_id = ?", projectID, userID).
+		First(&member).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: project=%d user=%d", ErrProjectMemberNotFound, projectID, userID)
+		}
+		return nil, fmt.Errorf("查询项目成员失败: %w", err)
+	}
+	return &member, nil
+}
+
+// ListMembers 获取项目的全部成员（包含用户信息）
+func (d *projectDAO) ListMembers(ctx context.Context, projectID uint) ([]models.ProjectMember, error) {
+	var members []models.ProjectMember
+	if err := d.db.WithContext(ctx).
+		Preload("User").
+		Where("project_id = ?", projectID).
+		Order("created_at").
+		Find(&members).Error; err != nil {
+		return nil, fmt.Errorf("查询项目成员失败: %w", err)
+	}
+	return members, nil
+}
+
+// AddMember 添加项目成员
+func (d *projectDAO) AddMember(ctx context.Context, member *models.ProjectMember) error {
+	if err := d.db.WithContext(ctx).Omit("User").Create(member).Error; err != nil {
+		return fmt.Errorf("添加项目成员失败: %w", err)
+	}
+	return nil
+}
+
+// UpdateMemberRole 修改项目成员角色
+func (d *projectDAO) UpdateMemberRole(ctx context.Context, projectID, userID uint, role string) error {
+	if err := d.db.WithContext(ctx).Model(&models.ProjectMember{}).
+		Where("project_id = ? AND user_id = ?", projectID, userID).
+		Update("role", role).Error; err != nil {
+		return fmt.Errorf("修改项目成员角色失败: %w", err)
+	}
+	return nil
+}
+
+// RemoveMember 移除项目成员
+func (d *projectDAO) RemoveMember(ctx context.Context, projectID, userID uint) error {
+	if err := d.db.WithContext(ctx).
+		Where("project_id = ? AND user_id = ?", projectID, userID).
+		Delete(&models.ProjectMember{}).Error; err != nil {
+		return fmt.Errorf("移除项目成员失败: %w", err)
+	}
+	return nil
+}
+
+// RemoveAllMembers 移除项目的全部成员（删除项目时使用）
+func (d *projectDAO) RemoveAllMembers(ctx context.Context, projectID uint) error {
+	if err := d.db.WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Delete(&models.ProjectMember{}).Error; err != nil {
+		return fmt.Errorf("移除项目成员失败: %w", err)
+	}
+	return nil
+}
+
+// WithTx 返回绑定事务的DAO
+func (d *projectDAO) WithTx(tx *gorm.DB) ProjectDAO {
+	return &projectDAO{db: tx}
+}
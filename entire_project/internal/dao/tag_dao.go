@@ -0,0 +1,212 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/models"
+)
+
+// 标签相关错误
+var (
+	ErrTagNotFound   = apperr.NotFound("tag_not_found", "标签不存在")
+	ErrTagNameExists = apperr.Conflict("tag_name_exists", "标签名称已存在")
+)
+
+// TagDAO 标签数据访问接口
+// 学习要点：多对多关系中"从表"的DAO设计，关联表的维护
+type TagDAO interface {
+	// 基础CRUD操作
+	Create(ctx context.Context, tag *models.Tag) error
+	GetByID(ctx context.Context, id uint) (*models.Tag, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]models.Tag, error)
+	GetByName(ctx context.Context, projectID *uint, name string) (*models.Tag, error)
+	Update(ctx context.Context, tag *models.Tag) error
+	Delete(ctx context.Context, id uint) error
+
+	// 查询操作
+	List(ctx context.Context, projectID *uint, offset, limit int) ([]models.Tag, int64, error)
+
+	// 关联操作
+	CountUsage(ctx context.Context, tagIDs []uint) (map[uint]int64, error)
+	DetachFromTasks(ctx context.Context, tagID uint) error
+	DeleteByProjectID(ctx context.Context, projectID uint) error
+
+	// 事务支持
+	WithTx(tx *gorm.DB) TagDAO
+}
+
+// tagDAO 标签DAO实现
+type tagDAO struct {
+	db *gorm.DB
+}
+
+// NewTagDAO 创建标签DAO实例
+func NewTagDAO(db *gorm.DB) TagDAO {
+	return &tagDAO{db: db}
+}
+
+// Create 创建标签
+func (d *tagDAO) Create(ctx context.Context, tag *models.Tag) error {
+	if err := d.db.WithContext(ctx).Create(tag).Error; err != nil {
+		if isDuplicateKeyError(err) {
+			return fmt.Errorf("%w: %s", ErrTagNameExists, tag.Name)
+		}
+		return fmt.Errorf("创建标签失败: %w", err)
+	}
+	return nil
+}
+
+// GetByID 根据ID获取标签
+func (d *tagDAO) GetByID(ctx context.Context, id uint) (*models.Tag, error) {
+	var tag models.Tag
+	err := d.db.WithContext(ctx).First(&tag, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: ID=%d", ErrTagNotFound, id)
+		}
+		return nil, fmt.Errorf("查询标签失败: %w", err)
+	}
+	return &tag, nil
+}
+
+// GetByIDs 根据ID列表批量获取标签，不存在的ID被忽略
+func (d *tagDAO) GetByIDs(ctx context.Context, ids []uint) ([]models.Tag, error) {
+	var tags []models.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	if err := d.db.WithContext(ctx).Where("id IN ?", ids).Find(&tags).Error; err != nil {
+		return nil, fmt.Errorf("查询标签失败: %w", err)
+	}
+	return tags, nil
+}
+
+// GetByName 在全局标签（projectID 为 nil）或指定项目的标签中按名称查找
+func (d *tagDAO) GetByName(ctx context.Context, projectID *uint, name string) (*models.Tag, error) {
+	var tag models.Tag
+	err := d.db.WithContext(ctx).Scopes(tagProjectScope(projectID)).Where("name = ?", name).First(&tag).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: name=%s", ErrTagNotFound, name)
+		}
+		return nil, fmt.Errorf("查询标签失败: %w", err)
+	}
+	return &tag, nil
+}
+
+// Update 更新标签
+func (d *tagDAO) Update(ctx context.Context, tag *models.Tag) error {
+	if err := d.db.WithContext(ctx).Save(tag).Error; err != nil {
+		if isDuplicateKeyError(err) {
+			return fmt.Errorf("%w: %s", ErrTagNameExists, tag.Name)
+		}
+		return fmt.Errorf("更新标签失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除标签
+// 学习要点：标签名称有唯一索引，软删除会让同名标签无法再次创建，因此这里使用物理删除
+func (d *tagDAO) Delete(ctx context.Context, id uint) error {
+	if err := d.db.WithContext(ctx).Unscoped().Delete(&models.Tag{}, id).Error; err != nil {
+		return fmt.Errorf("删除标签失败: %w", err)
+	}
+	return nil
+}
+
+// List 获取全局标签（projectID 为 nil）或指定项目的标签列表
+func (d *tagDAO) List(ctx context.Context, projectID *uint, offset, limit int) ([]models.Tag, int64, error) {
+	var tags []models.Tag
+	var total int64
+
+	query := d.db.WithContext(ctx).Model(&models.Tag{}).Scopes(tagProjectScope(projectID))
+
+	// 统计总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计标签总数失败: %w", err)
+	}
+
+	// 查询列表
+	if err := query.
+		Order("name").
+		Offset(offset).
+		Limit(limit).
+		Find(&tags).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询标签列表失败: %w", err)
+	}
+
+	return tags, total, nil
+}
+
+// CountUsage 统计标签被多少个任务使用
+// 学习要点：关联表与主表JOIN，排除已软删除的任务
+func (d *tagDAO) CountUsage(ctx context.Context, tagIDs []uint) (map[uint]int64, error) {
+	usage := make(map[uint]int64, len(tagIDs))
+	if len(tagIDs) == 0 {
+		return usage, nil
+	}
+
+	type TagCount struct {
+		TagID uint  `json:"tag_id"`
+		Count int64 `json:"count"`
+	}
+
+	var results []TagCount
+	err := d.db.WithContext(ctx).
+		Table("task_tags").
+		Select("task_tags.tag_id, COUNT(*) as count").
+		Joins("JOIN tasks ON tasks.id = task_tags.task_id AND tasks.deleted_at IS NULL").
+		Where("task_tags.tag_id IN ?", tagIDs).
+		Group("task_tags.tag_id").
+		Scan(&results).Error
+
+	if err != nil {
+		return nil, fmt.Errorf("统计标签使用次数失败: %w", err)
+	}
+
+	for _, result := range results {
+		usage[result.TagID] = result.Count
+	}
+
+	return usage, nil
+}
+
+// DetachFromTasks 解除标签与所有任务的关联
+// 学习要点：直接操作多对多关联表
+func (d *tagDAO) DetachFromTasks(ctx context.Context, tagID uint) error {
+	if err := d.db.WithContext(ctx).Exec("DELETE FROM task_tags WHERE tag_id = ?", tagID).Error; err != nil {
+		return fmt.Errorf("解除标签关联失败: %w", err)
+	}
+	return nil
+}
+
+// DeleteByProjectID 删除项目的全部标签（删除项目时使用，调用前项目中已经没有任务）
+func (d *tagDAO) DeleteByProjectID(ctx context.Context, projectID uint) error {
+	if err := d.db.WithContext(ctx).Unscoped().Where("project_id = ?", projectID).Delete(&models.Tag{}).Error; err != nil {
+		return fmt.Errorf("删除项目标签失败: %w", err)
+	}
+	return nil
+}
+
+// tagProjectScope 限定标签范围：projectID 为 nil 时只查全局标签
+// 学习要点：GORM Scopes 把可复用的查询条件封装成函数
+func tagProjectScope(projectID *uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if projectID == nil {
+			return db.Where("project_id IS NULL")
+		}
+		return db.Where("project_id = ?", *projectID)
+	}
+}
+
+// WithTx 使用事务
+func (d *tagDAO) WithTx(tx *gorm.DB) TagDAO {
+	return &tagDAO{db: tx}
+}
+
+// 确保实现了接口
+var _ TagDAO = (*tagDAO)(nil)
@@ -6,9 +6,14 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"task-management-system/internal/apperr"
 	"task-management-system/internal/models"
 )
 
+// ErrTaskNotFound 任务不存在
+var ErrTaskNotFound = apperr.NotFound("task_not_found", "任务不存在")
+
 // TaskDAO 任务数据访问接口
 // 学习要点：复杂业务对象的DAO设计，关联查询
 type TaskDAO interface {
@@ -17,7 +22,10 @@ type TaskDAO interface {
 	GetByID(ctx context.Context, id uint) (*models.Task, error)
 	GetByIDWithAssociations(ctx context.Context, id uint) (*models.Task, error)
 	Update(ctx context.Context, task *models.Task) error
+	UpdateFields(ctx context.Context, id uint, updates map[string]interface{}) error
+	UpdateFieldsIfStatus(ctx context.Context, id uint, status int, updates map[string]interface{}) (bool, error)
 	Delete(ctx context.Context, id uint) error
+	DeleteByUserID(ctx context.Context, userID uint) error
 	
 	// 查询操作
 	List(ctx context.Context, offset, limit int) ([]models.Task, int64, error)
@@ -36,13 +44,24 @@ type TaskDAO interface {
 	CountByStatus(ctx context.Context, status int) (int64, error)
 	CountByUserID(ctx context.Context, userID uint) (int64, error)
 	CountOverdue(ctx context.Context) (int64, error)
+	CountOverdueByUserID(ctx context.Context, userID uint) (int64, error)
 	GetStatusStats(ctx context.Context) (map[int]int64, error)
+	GetPriorityStats(ctx context.Context) (map[int]int64, error)
+	GetCompletionTrend(ctx context.Context, since time.Time) ([]models.DailyCount, error)
 	GetUserTaskStats(ctx context.Context, userID uint) (map[int]int64, error)
 	
 	// 关联操作
 	AddTags(ctx context.Context, taskID uint, tagIDs []uint) error
 	RemoveTags(ctx context.Context, taskID uint, tagIDs []uint) error
 	ReplaceTags(ctx context.Context, taskID uint, tagIDs []uint) error
+	AddAssignees(ctx context.Context, taskID uint, userIDs []uint) error
+	RemoveAssignee(ctx context.Context, taskID uint, userID uint) error
+	AddWatcher(ctx context.Context, taskID uint, userID uint) error
+	RemoveWatcher(ctx context.Context, taskID uint, userID uint) error
+	AddDependency(ctx context.Context, taskID uint, blockerID uint) error
+	RemoveDependency(ctx context.Context, taskID uint, blockerID uint) error
+	GetBlockerIDs(ctx context.Context, taskIDs []uint) (map[uint][]uint, error)
+	GetBlockedTaskIDs(ctx context.Context, blockerID uint) ([]uint, error)
 	
 	// 批量操作
 	BatchUpdateStatus(ctx context.Context, ids []uint, status int) error
@@ -50,6 +69,11 @@ type TaskDAO interface {
 	
 	// 事务支持
 	WithTx(tx *gorm.DB) TaskDAO
+	
+	// InProject 返回只统计指定项目任务的DAO，用于项目维度的统计查询
+	InProject(projectID uint) TaskDAO
+	// VisibleTo 返回只统计用户可见任务（全局任务和所在项目的任务）的DAO
+	VisibleTo(viewerID uint) TaskDAO
 }
 
 // TaskFilter 任务查询过滤器
@@ -59,6 +83,9 @@ type TaskFilter struct {
 	Status     *int       `json:"status"`
 	Priority   *int       `json:"priority"`
 	TagID      *uint      `json:"tag_id"`
+	AssigneeID *uint      `json:"assignee_id"`
+	ProjectID  *uint      `json:"project_id"`
+	VisibleTo  *uint      `json:"visible_to"` // 非空时只返回全局任务和该用户所在项目的任务，0 表示未登录
 	Keyword    string     `json:"keyword"`
 	StartDate  *time.Time `json:"start_date"`
 	EndDate    *time.Time `json:"end_date"`
@@ -93,7 +120,7 @@ func (d *taskDAO) GetByID(ctx context.Context, id uint) (*models.Task, error) {
 	err := d.db.WithContext(ctx).First(&task, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("任务不存在: ID=%d", id)
+			return nil, fmt.Errorf("%w: ID=%d", ErrTaskNotFound, id)
 		}
 		return nil, fmt.Errorf("查询任务失败: %w", err)
 	}
@@ -101,20 +128,27 @@ func (d *taskDAO) GetByID(ctx context.Context, id uint) (*models.Task, error) {
 }
 
 // GetByIDWithAssociations 根据ID获取任务（包含关联数据）
-// 学习要点：预加载关联数据，避免N+1查询问题
+// 学习要点：预加载关联数据，避免N+1查询问题；子任务加载后汇总进度。
+// 已软删除的子任务和前置任务不会被预加载，因此删除任务时不需要清理依赖关系
 func (d *taskDAO) GetByIDWithAssociations(ctx context.Context, id uint) (*models.Task, error) {
 	var task models.Task
+	byID := func(db *gorm.DB) *gorm.DB { return db.Order("tasks.id") }
 	err := d.db.WithContext(ctx).
 		Preload("User").
 		Preload("Tags").
+		Preload("Assignees").
+		Preload("Watchers").
+		Preload("Subtasks", byID).
+		Preload("BlockedBy", byID).
 		First(&task, id).Error
 		
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("任务不存在: ID=%d", id)
+			return nil, fmt.Errorf("%w: ID=%d", ErrTaskNotFound, id)
 		}
 		return nil, fmt.Errorf("查询任务失败: %w", err)
 	}
+	task.Progress = task.SubtaskProgress()
 	return &task, nil
 }
 
@@ -126,6 +160,32 @@ func (d *taskDAO) Update(ctx context.Context, task *models.Task) error {
 	return nil
 }
 
+// UpdateFields 部分更新任务字段
+// 学习要点：使用 map 更新可以把字段更新为零值，且不会覆盖未修改的字段
+func (d *taskDAO) UpdateFields(ctx context.Context, id uint, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	if err := d.db.WithContext(ctx).Model(&models.Task{}).
+		Where("id = ?", id).
+		Updates(updates).Error; err != nil {
+		return fmt.Errorf("更新任务失败: %w", err)
+	}
+	return nil
+}
+
+// UpdateFieldsIfStatus 任务仍处于指定状态时才更新字段，返回是否更新成功
+// 学习要点：条件更新（乐观锁）防止并发请求基于同一个旧状态各自完成状态流转
+func (d *taskDAO) UpdateFieldsIfStatus(ctx context.Context, id uint, status int, updates map[string]interface{}) (bool, error) {
+	result := d.db.WithContext(ctx).Model(&models.Task{}).
+		Where("id = ? AND status = ?", id, status).
+		Updates(updates)
+	if result.Error != nil {
+		return false, fmt.Errorf("更新任务失败: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // Delete 删除任务
 func (d *taskDAO) Delete(ctx context.Context, id uint) error {
 	if err := d.db.WithContext(ctx).Delete(&models.Task{}, id).Error; err != nil {
@@ -134,6 +194,14 @@ func (d *taskDAO) Delete(ctx context.Context, id uint) error {
 	return nil
 }
 
+// DeleteByUserID 删除用户的所有任务（软删除）
+func (d *taskDAO) DeleteByUserID(ctx context.Context, userID uint) error {
+	if err := d.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.Task{}).Error; err != nil {
+		return fmt.Errorf("删除用户任务失败: %w", err)
+	}
+	return nil
+}
+
 // List 获取任务列表
 func (d *taskDAO) List(ctx context.Context, offset, limit int) ([]models.Task, int64, error) {
 	var tasks []models.Task
@@ -209,6 +277,19 @@ func (d *taskDAO) GetTasksByFilter(ctx context.Context, filter TaskFilter) ([]mo
 			Where("task_tags.tag_id = ?", *filter.TagID)
 	}
 	
+	// 学习要点：用子查询而不是 JOIN，task_assignees 的 user_id 不会与 tasks.user_id 混淆
+	if filter.AssigneeID != nil {
+		query = query.Where("tasks.id IN (SELECT task_id FROM task_assignees WHERE user_id = ?)", *filter.AssigneeID)
+	}
+	
+	if filter.ProjectID != nil {
+		query = query.Where("tasks.project_id = ?", *filter.ProjectID)
+	}
+	
+	if filter.VisibleTo != nil {
+		query = query.Where("tasks.project_id IS NULL OR tasks.project_id IN (SELECT project_id FROM project_members WHERE user_id = ?)", *filter.VisibleTo)
+	}
+	
 	if filter.Keyword != "" {
 		searchPattern := "%" + filter.Keyword + "%"
 		query = query.Where("title LIKE ? OR description LIKE ?", searchPattern, searchPattern)
@@ -252,6 +333,7 @@ func (d *taskDAO) GetTasksByFilter(ctx context.Context, filter TaskFilter) ([]mo
 	if err := query.
 		Preload("User").
 		Preload("Tags").
+		Preload("Assignees").
 		Order(orderBy).
 		Offset(offset).
 		Limit(limit).
@@ -337,6 +419,51 @@ func (d *taskDAO) GetStatusStats(ctx context.Context) (map[int]int64, error) {
 	return stats, nil
 }
 
+// GetPriorityStats 获取优先级统计
+func (d *taskDAO) GetPriorityStats(ctx context.Context) (map[int]int64, error) {
+	type PriorityCount struct {
+		Priority int   `json:"priority"`
+		Count    int64 `json:"count"`
+	}
+	
+	var results []PriorityCount
+	err := d.db.WithContext(ctx).
+		Model(&models.Task{}).
+		Select("priority, COUNT(*) as count").
+		Group("priority").
+		Find(&results).Error
+		
+	if err != nil {
+		return nil, fmt.Errorf("查询优先级统计失败: %w", err)
+	}
+	
+	stats := make(map[int]int64)
+	for _, result := range results {
+		stats[result.Priority] = result.Count
+	}
+	
+	return stats, nil
+}
+
+// GetCompletionTrend 获取每日完成任务数
+// 学习要点：按日期分组统计，DATE_FORMAT 把时间转换为日期字符串
+func (d *taskDAO) GetCompletionTrend(ctx context.Context, since time.Time) ([]models.DailyCount, error) {
+	var results []models.DailyCount
+	err := d.db.WithContext(ctx).
+		Model(&models.Task{}).
+		Select("DATE_FORMAT(end_time, '%Y-%m-%d') as date, COUNT(*) as count").
+		Where("status = ? AND end_time >= ?", models.TaskStatusCompleted, since).
+		Group("date").
+		Order("date").
+		Find(&results).Error
+		
+	if err != nil {
+		return nil, fmt.Errorf("查询完成趋势失败: %w", err)
+	}
+	
+	return results, nil
+}
+
 // GetUserTaskStats 获取用户任务统计
 func (d *taskDAO) GetUserTaskStats(ctx context.Context, userID uint) (map[int]int64, error) {
 	type StatusCount struct {
@@ -459,39 +586,213 @@ func (d *taskDAO) CountOverdue(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
+// CountOverdueByUserID 统计用户的过期任务数
+func (d *taskDAO) CountOverdueByUserID(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	if err := d.db.WithContext(ctx).Model(&models.Task{}).
+		Where("user_id = ? AND due_date < ? AND status != ?", userID, time.Now(), models.TaskStatusCompleted).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("统计用户过期任务数失败: %w", err)
+	}
+	return count, nil
+}
+
 // WithTx 使用事务
 func (d *taskDAO) WithTx(tx *gorm.DB) TaskDAO {
 	return &taskDAO{db: tx}
 }
 
-// 其他方法的实现...
+// InProject 返回只统计指定项目任务的DAO
+// 学习要点：在基础 *gorm.DB 上附加条件，统计方法不用逐个增加项目参数；
+// 条件会作用于所有查询，因此只用于统计，不用于写操作
+func (d *taskDAO) InProject(projectID uint) TaskDAO {
+	return &taskDAO{db: d.db.Where("tasks.project_id = ?", projectID)}
+}
+
+// VisibleTo 返回只统计用户可见任务的DAO，viewerID 为 0 表示未登录，只能看到全局任务
+// 注意：与 InProject 一样只用于统计查询
+func (d *taskDAO) VisibleTo(viewerID uint) TaskDAO {
+	return &taskDAO{db: d.db.Where("tasks.project_id IS NULL OR tasks.project_id IN (SELECT project_id FROM project_members WHERE user_id = ?)", viewerID)}
+}
+
+// ListByStatus 根据状态获取任务列表
 func (d *taskDAO) ListByStatus(ctx context.Context, status int, offset, limit int) ([]models.Task, int64, error) {
-	// 实现类似 ListByUserID 的逻辑
-	return nil, 0, nil
+	var tasks []models.Task
+	var total int64
+	
+	query := d.db.WithContext(ctx).Model(&models.Task{}).Where("status = ?", status)
+	
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("统计任务总数失败: %w", err)
+	}
+	
+	if err := query.
+		Preload("Tags").
+		Offset(offset).
+		Limit(limit).
+		Order("created_at DESC").
+		Find(&tasks).Error; err != nil {
+		return nil, 0, fmt.Errorf("查询任务列表失败: %w", err)
+	}
+	
+	return tasks, total, nil
 }
 
+// Search 按标题或描述搜索任务
 func (d *taskDAO) Search(ctx context.Context, keyword string, offset, limit int) ([]models.Task, int64, error) {
-	// 实现搜索逻辑
-	return nil, 0, nil
+	return d.GetTasksByFilter(ctx, TaskFilter{
+		Keyword:   keyword,
+		OrderDesc: true,
+		Page:      offset/max(limit, 1) + 1,
+		PageSize:  limit,
+	})
 }
 
+// GetTasksByPriority 根据优先级获取任务
 func (d *taskDAO) GetTasksByPriority(ctx context.Context, priority int) ([]models.Task, error) {
-	// 实现按优先级查询逻辑
-	return nil, nil
+	var tasks []models.Task
+	if err := d.db.WithContext(ctx).
+		Where("priority = ?", priority).
+		Order("created_at DESC").
+		Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("查询任务失败: %w", err)
+	}
+	return tasks, nil
 }
 
+// GetTasksByDateRange 获取截止日期在指定范围内的任务
 func (d *taskDAO) GetTasksByDateRange(ctx context.Context, startDate, endDate time.Time) ([]models.Task, error) {
-	// 实现按日期范围查询逻辑
-	return nil, nil
+	var tasks []models.Task
+	if err := d.db.WithContext(ctx).
+		Where("due_date BETWEEN ? AND ?", startDate, endDate).
+		Order("due_date").
+		Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("查询任务失败: %w", err)
+	}
+	return tasks, nil
 }
 
+// RemoveTags 移除任务的指定标签
 func (d *taskDAO) RemoveTags(ctx context.Context, taskID uint, tagIDs []uint) error {
-	// 实现移除标签逻辑
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	
+	if err := d.db.WithContext(ctx).
+		Exec("DELETE FROM task_tags WHERE task_id = ? AND tag_id IN ?", taskID, tagIDs).Error; err != nil {
+		return fmt.Errorf("移除任务标签失败: %w", err)
+	}
+	return nil
+}
+
+// AddAssignees 为任务添加负责人，已经是负责人的用户被忽略
+// 学习要点：关联表以 (task_id, user_id) 为主键，GORM 追加关联时会忽略重复记录
+func (d *taskDAO) AddAssignees(ctx context.Context, taskID uint, userIDs []uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	users := make([]models.User, 0, len(userIDs))
+	for _, id := range userIDs {
+		users = append(users, models.User{BaseModel: models.BaseModel{ID: id}})
+	}
+
+	task := &models.Task{BaseModel: models.BaseModel{ID: taskID}}
+	if err := d.db.WithContext(ctx).Model(task).Omit("Assignees.*").Association("Assignees").Append(users); err != nil {
+		return fmt.Errorf("添加任务负责人失败: %w", err)
+	}
 	return nil
 }
 
+// RemoveAssignee 移除任务负责人
+func (d *taskDAO) RemoveAssignee(ctx context.Context, taskID uint, userID uint) error {
+	if err := d.db.WithContext(ctx).
+		Exec("DELETE FROM task_assignees WHERE task_id = ? AND user_id = ?", taskID, userID).Error; err != nil {
+		return fmt.Errorf("移除任务负责人失败: %w", err)
+	}
+	return nil
+}
+
+// AddWatcher 关注任务，重复关注被忽略
+func (d *taskDAO) AddWatcher(ctx context.Context, taskID uint, userID uint) error {
+	task := &models.Task{BaseModel: models.BaseModel{ID: taskID}}
+	user := &models.User{BaseModel: models.BaseModel{ID: userID}}
+	if err := d.db.WithContext(ctx).Model(task).Omit("Watchers.*").Association("Watchers").Append(user); err != nil {
+		return fmt.Errorf("关注任务失败: %w", err)
+	}
+	return nil
+}
+
+// RemoveWatcher 取消关注任务
+func (d *taskDAO) RemoveWatcher(ctx context.Context, taskID uint, userID uint) error {
+	if err := d.db.WithContext(ctx).
+		Exec("DELETE FROM task_watchers WHERE task_id = ? AND user_id = ?", taskID, userID).Error; err != nil {
+		return fmt.Errorf("取消关注任务失败: %w", err)
+	}
+	return nil
+}
+
+// AddDependency 添加任务依赖，已存在的依赖被忽略
+func (d *taskDAO) AddDependency(ctx context.Context, taskID uint, blockerID uint) error {
+	dependency := &models.TaskDependency{TaskID: taskID, BlockerID: blockerID}
+	if err := d.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(dependency).Error; err != nil {
+		return fmt.Errorf("添加任务依赖失败: %w", err)
+	}
+	return nil
+}
+
+// RemoveDependency 移除任务依赖
+func (d *taskDAO) RemoveDependency(ctx context.Context, taskID uint, blockerID uint) error {
+	if err := d.db.WithContext(ctx).
+		Where("task_id = ? AND blocker_id = ?", taskID, blockerID).
+		Delete(&models.TaskDependency{}).Error; err != nil {
+		return fmt.Errorf("移除任务依赖失败: %w", err)
+	}
+	return nil
+}
+
+// GetBlockerIDs 批量获取任务的前置任务ID，键为任务ID
+// 学习要点：一次查询一层任务，遍历依赖图时查询次数等于图的深度而不是节点数；
+// 已软删除的前置任务不再阻塞，也不参与环检测
+func (d *taskDAO) GetBlockerIDs(ctx context.Context, taskIDs []uint) (map[uint][]uint, error) {
+	blockers := make(map[uint][]uint)
+	if len(taskIDs) == 0 {
+		return blockers, nil
+	}
+
+	var dependencies []models.TaskDependency
+	if err := d.db.WithContext(ctx).
+		Joins("JOIN tasks ON tasks.id = task_dependencies.blocker_id AND tasks.deleted_at IS NULL").
+		Where("task_dependencies.task_id IN ?", taskIDs).
+		Find(&dependencies).Error; err != nil {
+		return nil, fmt.Errorf("查询任务依赖失败: %w", err)
+	}
+	for _, dependency := range dependencies {
+		blockers[dependency.TaskID] = append(blockers[dependency.TaskID], dependency.BlockerID)
+	}
+	return blockers, nil
+}
+
+// GetBlockedTaskIDs 获取被指定任务阻塞的任务ID
+func (d *taskDAO) GetBlockedTaskIDs(ctx context.Context, blockerID uint) ([]uint, error) {
+	var ids []uint
+	if err := d.db.WithContext(ctx).Model(&models.TaskDependency{}).
+		Where("blocker_id = ?", blockerID).
+		Pluck("task_id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("查询被阻塞的任务失败: %w", err)
+	}
+	return ids, nil
+}
+
+// BatchDelete 批量删除任务（软删除）
 func (d *taskDAO) BatchDelete(ctx context.Context, ids []uint) error {
-	// 实现批量删除逻辑
+	if len(ids) == 0 {
+		return nil
+	}
+	
+	if err := d.db.WithContext(ctx).Where("id IN ?", ids).Delete(&models.Task{}).Error; err != nil {
+		return fmt.Errorf("批量删除任务失败: %w", err)
+	}
 	return nil
 }
 
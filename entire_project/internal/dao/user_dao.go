@@ -8,18 +8,27 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+	"task-management-system/internal/apperr"
 	"task-management-system/internal/models"
 )
 
+// ErrUserNotFound 用户不存在
+var ErrUserNotFound = apperr.NotFound("user_not_found", "用户不存在")
+
 // UserDAO 用户数据访问接口
 // 学习要点：接口定义，抽象数据访问操作
 type UserDAO interface {
 	// 基础CRUD操作
 	Create(ctx context.Context, user *models.User) error
 	GetByID(ctx context.Context, id uint) (*models.User, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]models.User, error)
 	GetByUsername(ctx context.Context, username string) (*models.User, error)
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
 	Update(ctx context.Context, user *models.User) error
+	UpdateFields(ctx context.Context, id uint, updates map[string]interface{}) error
+	UpdatePassword(ctx context.Context, id uint, hashedPassword string) error
+	UpdateRole(ctx context.Context, id uint, role string) error
+	UpdateLastLogin(ctx context.Context, id uint, loginAt time.Time) error
 	Delete(ctx context.Context, id uint) error
 	
 	// 查询操作
@@ -31,6 +40,8 @@ type UserDAO interface {
 	GetActiveUsers(ctx context.Context) ([]models.User, error)
 	GetUsersWithTasks(ctx context.Context) ([]models.User, error)
 	CountByStatus(ctx context.Context, status int) (int64, error)
+	CountActiveSince(ctx context.Context, since time.Time) (int64, error)
+	GetRoleStats(ctx context.Context) (map[string]int64, error)
 	
 	// 批量操作
 	BatchCreate(ctx context.Context, users []models.User) error
@@ -67,13 +78,25 @@ func (d *userDAO) GetByID(ctx context.Context, id uint) (*models.User, error) {
 	err := d.db.WithContext(ctx).First(&user, id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("用户不存在: ID=%d", id)
+			return nil, fmt.Errorf("%w: ID=%d", ErrUserNotFound, id)
 		}
 		return nil, fmt.Errorf("查询用户失败: %w", err)
 	}
 	return &user, nil
 }
 
+// GetByIDs 根据ID列表批量获取用户，不存在的ID被忽略
+func (d *userDAO) GetByIDs(ctx context.Context, ids []uint) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	if err := d.db.WithContext(ctx).Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+	return users, nil
+}
+
 // GetByUsername 根据用户名获取用户
 // 学习要点：条件查询，唯一字段查询
 func (d *userDAO) GetByUsername(ctx context.Context, username string) (*models.User, error) {
@@ -81,7 +104,7 @@ func (d *userDAO) GetByUsername(ctx context.Context, username string) (*models.U
 	err := d.db.WithContext(ctx).Where("username = ?", username).First(&user).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("用户不存在: username=%s", username)
+			return nil, fmt.Errorf("%w: username=%s", ErrUserNotFound, username)
 		}
 		return nil, fmt.Errorf("查询用户失败: %w", err)
 	}
@@ -94,7 +117,7 @@ func (d *userDAO) GetByEmail(ctx context.Context, email string) (*models.User, e
 	err := d.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("用户不存在: email=%s", email)
+			return nil, fmt.Errorf("%w: email=%s", ErrUserNotFound, email)
 		}
 		return nil, fmt.Errorf("查询用户失败: %w", err)
 	}
@@ -110,6 +133,52 @@ func (d *userDAO) Update(ctx context.Context, user *models.User) error {
 	return nil
 }
 
+// UpdateFields 部分更新用户字段
+// 学习要点：只写入修改的列，不会用读到的旧数据覆盖并发修改的密码、状态等字段
+func (d *userDAO) UpdateFields(ctx context.Context, id uint, updates map[string]interface{}) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	if err := d.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ?", id).
+		Updates(updates).Error; err != nil {
+		return fmt.Errorf("更新用户失败: %w", err)
+	}
+	return nil
+}
+
+// UpdatePassword 更新用户密码
+// 学习要点：只更新单个字段，避免 Save 覆盖其他并发修改
+func (d *userDAO) UpdatePassword(ctx context.Context, id uint, hashedPassword string) error {
+	if err := d.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ?", id).
+		Update("password", hashedPassword).Error; err != nil {
+		return fmt.Errorf("更新用户密码失败: %w", err)
+	}
+	return nil
+}
+
+// UpdateRole 更新用户角色
+func (d *userDAO) UpdateRole(ctx context.Context, id uint, role string) error {
+	if err := d.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ?", id).
+		Update("role", role).Error; err != nil {
+		return fmt.Errorf("更新用户角色失败: %w", err)
+	}
+	return nil
+}
+
+// UpdateLastLogin 更新最后登录时间
+// 学习要点：UpdateColumn 只写一个字段，也不修改 updated_at，登录不算修改用户资料
+func (d *userDAO) UpdateLastLogin(ctx context.Context, id uint, loginAt time.Time) error {
+	if err := d.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ?", id).
+		UpdateColumn("last_login_at", loginAt).Error; err != nil {
+		return fmt.Errorf("更新最后登录时间失败: %w", err)
+	}
+	return nil
+}
+
 // Delete 删除用户
 // 学习要点：软删除操作
 func (d *userDAO) Delete(ctx context.Context, id uint) error {
@@ -218,6 +287,45 @@ func (d *userDAO) CountByStatus(ctx context.Context, status int) (int64, error)
 	return count, nil
 }
 
+// CountActiveSince 统计指定时间之后登录过的正常用户数
+// 学习要点：基于 last_login_at 的活跃度统计
+func (d *userDAO) CountActiveSince(ctx context.Context, since time.Time) (int64, error) {
+	var count int64
+	if err := d.db.WithContext(ctx).Model(&models.User{}).
+		Where("status = ? AND last_login_at >= ?", 1, since).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("统计活跃用户数失败: %w", err)
+	}
+	return count, nil
+}
+
+// GetRoleStats 按角色统计用户数
+// 学习要点：GROUP BY聚合查询
+func (d *userDAO) GetRoleStats(ctx context.Context) (map[string]int64, error) {
+	type RoleCount struct {
+		Role  string `json:"role"`
+		Count int64  `json:"count"`
+	}
+	
+	var results []RoleCount
+	err := d.db.WithContext(ctx).
+		Model(&models.User{}).
+		Select("role, COUNT(*) as count").
+		Group("role").
+		Find(&results).Error
+		
+	if err != nil {
+		return nil, fmt.Errorf("查询角色统计失败: %w", err)
+	}
+	
+	stats := make(map[string]int64)
+	for _, result := range results {
+		stats[result.Role] = result.Count
+	}
+	
+	return stats, nil
+}
+
 // BatchCreate 批量创建用户
 // 学习要点：批量操作，性能优化
 func (d *userDAO) BatchCreate(ctx context.Context, users []models.User) error {
@@ -0,0 +1,413 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationFiles 编译进二进制的迁移文件
+// 学习要点：embed 让部署时不需要额外拷贝 SQL 文件，迁移版本与代码版本始终一致
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// 迁移锁配置
+// 学习要点：MySQL 的 GET_LOCK 是连接级别的命名锁，多个副本同时启动时只有一个能执行迁移
+const (
+	migrationLockName    = "task_management_system:schema_migrations"
+	migrationLockTimeout = 60 // 等待锁的最长时间（秒）
+)
+
+// migrationFilePattern 迁移文件命名规则：<版本号>_<名称>.<up|down>.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration 一个版本的迁移
+type Migration struct {
+	Version int64  // 版本号
+	Name    string // 名称
+	UpSQL   string // 升级脚本
+	DownSQL string // 回滚脚本
+}
+
+// MigrationStatus 迁移的执行状态
+type MigrationStatus struct {
+	Version   int64      // 版本号
+	Name      string     // 名称
+	AppliedAt *time.Time // 执行时间，nil 表示尚未执行
+}
+
+// schemaMigration schema_migrations 表的一行，记录已执行的版本
+type schemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName 自定义表名
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator 版本化数据库迁移
+// 学习要点：每个版本有成对的 up/down 脚本，已执行的版本记录在 schema_migrations 表中，
+// 可以升级到最新、回滚一步或迁移到指定版本
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator 使用内置的迁移文件创建迁移器
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Latest 返回最新的迁移版本号，没有迁移时返回0
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up 执行所有未执行的迁移
+// 注意：滚动发布时旧版本实例可能遇到由新版本执行过的迁移，这种情况只告警不回滚
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		target := m.Latest()
+		for version := range applied {
+			if version > target {
+				slog.WarnContext(ctx, "数据库已执行更新的迁移", "version", version, "latest", m.Latest())
+				target = version
+			}
+		}
+		return m.migrate(ctx, conn, applied, target)
+	})
+}
+
+// Down 回滚最近执行的一个迁移
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		// 目标版本是当前最高已执行版本的前一个已执行版本
+		var current, target int64
+		for version := range applied {
+			if version > current {
+				current = version
+			}
+		}
+		if current == 0 {
+			slog.InfoContext(ctx, "没有可回滚的迁移")
+			return nil
+		}
+		for version := range applied {
+			if version < current && version > target {
+				target = version
+			}
+		}
+		return m.migrate(ctx, conn, applied, target)
+	})
+}
+
+// To 迁移到指定版本：高于目标的已执行版本会被回滚，不高于目标的未执行版本会被执行
+// target 为0表示回滚全部迁移
+func (m *Migrator) To(ctx context.Context, target int64) error {
+	if target != 0 && m.find(target) == nil {
+		return fmt.Errorf("迁移版本不存在: %d", target)
+	}
+
+	return m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		return m.migrate(ctx, conn, applied, target)
+	})
+}
+
+// Baseline 把不高于目标版本的未执行迁移标记为已执行，不执行脚本
+// 学习要点：用于接入已经由 AutoMigrate 建好表的数据库，表结构已经是目标版本，只需要补上迁移记录
+func (m *Migrator) Baseline(ctx context.Context, target int64) error {
+	if m.find(target) == nil {
+		return fmt.Errorf("迁移版本不存在: %d", target)
+	}
+
+	return m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		ups, downs, err := planMigrations(m.migrations, applied, target)
+		if err != nil {
+			return err
+		}
+		if len(downs) > 0 {
+			return fmt.Errorf("数据库已执行高于 %d 的迁移，不能标记基线", target)
+		}
+
+		for _, migration := range ups {
+			record := &schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}
+			if err := conn.WithContext(ctx).Create(record).Error; err != nil {
+				return fmt.Errorf("写入迁移记录失败: %w", err)
+			}
+			slog.InfoContext(ctx, "已标记迁移为已执行", "version", migration.Version, "name", migration.Name)
+		}
+		return nil
+	})
+}
+
+// Status 返回所有迁移的执行状态（按版本号升序）
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.ensureTable(ctx, m.db); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			appliedAt := appliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// migrate 在已持有锁的连接上把数据库迁移到目标版本
+func (m *Migrator) migrate(ctx context.Context, conn *gorm.DB, applied map[int64]time.Time, target int64) error {
+	ups, downs, err := planMigrations(m.migrations, applied, target)
+	if err != nil {
+		return err
+	}
+
+	// 注意：MySQL 的 DDL 会隐式提交，无法放进事务；每个版本执行成功后立即记录，
+	// 失败时停在失败的版本，修复脚本后可以重新执行
+	for _, migration := range downs {
+		if err := execScript(ctx, conn, migration.DownSQL); err != nil {
+			return fmt.Errorf("回滚迁移 %04d_%s 失败: %w", migration.Version, migration.Name, err)
+		}
+		if err := conn.WithContext(ctx).Delete(&schemaMigration{}, migration.Version).Error; err != nil {
+			return fmt.Errorf("删除迁移记录失败: %w", err)
+		}
+		slog.InfoContext(ctx, "已回滚迁移", "version", migration.Version, "name", migration.Name)
+	}
+
+	for _, migration := range ups {
+		if err := execScript(ctx, conn, migration.UpSQL); err != nil {
+			return fmt.Errorf("执行迁移 %04d_%s 失败: %w", migration.Version, migration.Name, err)
+		}
+		record := &schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}
+		if err := conn.WithContext(ctx).Create(record).Error; err != nil {
+			return fmt.Errorf("写入迁移记录失败: %w", err)
+		}
+		slog.InfoContext(ctx, "已执行迁移", "version", migration.Version, "name", migration.Name)
+	}
+
+	return nil
+}
+
+// withLock 获取迁移锁后在同一个连接上执行 fn
+// 学习要点：GET_LOCK 绑定在数据库连接上，必须用 db.Connection 固定连接，
+// 否则连接池可能把加锁和解锁分配到不同连接
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		var acquired sql.NullInt64
+		if err := conn.Raw("SELECT GET_LOCK(?, ?)", migrationLockName, migrationLockTimeout).Scan(&acquired).Error; err != nil {
+			return fmt.Errorf("获取迁移锁失败: %w", err)
+		}
+		if !acquired.Valid || acquired.Int64 != 1 {
+			return fmt.Errorf("获取迁移锁超时（%d秒），可能有其他实例正在执行迁移", migrationLockTimeout)
+		}
+		defer func() {
+			// 请求取消后仍要释放锁，否则锁会跟着连接回到连接池
+			if err := conn.WithContext(context.Background()).Exec("SELECT RELEASE_LOCK(?)", migrationLockName).Error; err != nil {
+				slog.WarnContext(ctx, "释放迁移锁失败", "error", err)
+			}
+		}()
+
+		if err := m.ensureTable(ctx, conn); err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+// ensureTable 创建 schema_migrations 表
+func (m *Migrator) ensureTable(ctx context.Context, conn *gorm.DB) error {
+	err := conn.WithContext(ctx).Exec("CREATE TABLE IF NOT EXISTS `schema_migrations` (" +
+		"`version` bigint NOT NULL, " +
+		"`name` varchar(255) NOT NULL, " +
+		"`applied_at` datetime(3) NOT NULL, " +
+		"PRIMARY KEY (`version`)" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4").Error
+	if err != nil {
+		return fmt.Errorf("创建迁移记录表失败: %w", err)
+	}
+	return nil
+}
+
+// applied 查询已执行的版本及其执行时间
+func (m *Migrator) applied(ctx context.Context, conn *gorm.DB) (map[int64]time.Time, error) {
+	var records []schemaMigration
+	if err := conn.WithContext(ctx).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("查询迁移记录失败: %w", err)
+	}
+
+	applied := make(map[int64]time.Time, len(records))
+	for _, record := range records {
+		applied[record.Version] = record.AppliedAt
+	}
+	return applied, nil
+}
+
+// find 根据版本号查找迁移
+func (m *Migrator) find(version int64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// planMigrations 计算迁移到目标版本需要执行的升级（升序）和回滚（降序）
+func planMigrations(migrations []Migration, applied map[int64]time.Time, target int64) (ups, downs []Migration, err error) {
+	known := make(map[int64]bool, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = true
+	}
+	// 数据库里有、代码里没有的版本无法回滚，说明当前二进制比数据库旧
+	for version := range applied {
+		if !known[version] && version > target {
+			return nil, nil, fmt.Errorf("数据库已执行的迁移 %d 在当前版本中不存在，无法迁移到 %d", version, target)
+		}
+	}
+
+	for _, migration := range migrations {
+		_, done := applied[migration.Version]
+		switch {
+		case migration.Version <= target && !done:
+			ups = append(ups, migration)
+		case migration.Version > target && done:
+			downs = append([]Migration{migration}, downs...)
+		}
+	}
+	return ups, downs, nil
+}
+
+// loadMigrations 从文件系统读取迁移文件，按版本号升序返回
+// 每个版本必须同时有 up 和 down 脚本
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取迁移目录失败: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		matches := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("迁移文件名不合法: %s", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("迁移版本号不合法: %s", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("读取迁移文件失败: %w", err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("迁移版本号重复: %d", version)
+		}
+
+		if matches[3] == "up" {
+			migration.UpSQL = string(content)
+		} else {
+			migration.DownSQL = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.UpSQL) == "" || strings.TrimSpace(migration.DownSQL) == "" {
+			return nil, fmt.Errorf("迁移 %04d_%s 缺少 up 或 down 脚本", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// execScript 逐条执行脚本中的 SQL 语句
+// 学习要点：MySQL 驱动默认不允许一次执行多条语句，这里按行尾的分号拆分
+func execScript(ctx context.Context, conn *gorm.DB, script string) error {
+	for _, statement := range splitStatements(script) {
+		if err := conn.WithContext(ctx).Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitStatements 按行尾分号拆分 SQL 脚本，忽略 -- 开头的注释行
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSuffix(strings.TrimSpace(current.String()), ";"))
+			current.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
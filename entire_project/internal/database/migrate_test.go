@@ -0,0 +1,115 @@
+package database
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	migrations, err := loadMigrations(migrationFiles, "migrations")
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, migration := range migrations {
+		if i > 0 {
+			assert.Greater(t, migration.Version, migrations[i-1].Version, "版本号必须递增")
+		}
+		assert.NotEmpty(t, splitStatements(migration.UpSQL), "%d 的 up 脚本为空", migration.Version)
+		assert.NotEmpty(t, splitStatements(migration.DownSQL), "%d 的 down 脚本为空", migration.Version)
+	}
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+	}{
+		{"缺少down脚本", fstest.MapFS{
+			"m/0001_init.up.sql": {Data: []byte("CREATE TABLE a (id int);")},
+		}},
+		{"文件名不合法", fstest.MapFS{
+			"m/init.sql": {Data: []byte("CREATE TABLE a (id int);")},
+		}},
+		{"版本号重复", fstest.MapFS{
+			"m/0001_a.up.sql":   {Data: []byte("SELECT 1;")},
+			"m/0001_a.down.sql": {Data: []byte("SELECT 1;")},
+			"m/0001_b.up.sql":   {Data: []byte("SELECT 1;")},
+			"m/0001_b.down.sql": {Data: []byte("SELECT 1;")},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadMigrations(tt.files, "m")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPlanMigrations(t *testing.T) {
+	migrations := []Migration{{Version: 1}, {Version: 2}, {Version: 3}}
+	now := time.Now()
+
+	versions := func(list []Migration) []int64 {
+		result := []int64{}
+		for _, m := range list {
+			result = append(result, m.Version)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name      string
+		applied   []int64
+		target    int64
+		wantUps   []int64
+		wantDowns []int64
+		wantErr   bool
+	}{
+		{"空库升级到最新", nil, 3, []int64{1, 2, 3}, []int64{}, false},
+		{"部分执行后升级", []int64{1}, 3, []int64{2, 3}, []int64{}, false},
+		{"回滚到指定版本（降序）", []int64{1, 2, 3}, 1, []int64{}, []int64{3, 2}, false},
+		{"回滚全部", []int64{1, 2}, 0, []int64{}, []int64{2, 1}, false},
+		{"补齐跳过的旧版本", []int64{1, 3}, 3, []int64{2}, []int64{}, false},
+		{"已是目标版本", []int64{1, 2}, 2, []int64{}, []int64{}, false},
+		{"数据库有未知的新版本时不能回滚", []int64{1, 2, 3, 4}, 2, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applied := make(map[int64]time.Time)
+			for _, v := range tt.applied {
+				applied[v] = now
+			}
+
+			ups, downs, err := planMigrations(migrations, applied, tt.target)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUps, versions(ups))
+			assert.Equal(t, tt.wantDowns, versions(downs))
+		})
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- 注释行
+CREATE TABLE a (
+  id int
+);
+
+CREATE INDEX idx_a ON a (id);
+DROP TABLE b`
+
+	statements := splitStatements(script)
+	require.Len(t, statements, 3)
+	assert.Equal(t, "CREATE TABLE a (\n  id int\n)", statements[0])
+	assert.Equal(t, "CREATE INDEX idx_a ON a (id)", statements[1])
+	assert.Equal(t, "DROP TABLE b", statements[2])
+}
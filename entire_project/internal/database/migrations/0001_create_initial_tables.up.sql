@@ -0,0 +1,58 @@
+-- 初始表结构：与 GORM AutoMigrate 生成的结构保持一致，用于新建数据库
+-- 注意：已经由 AutoMigrate 建好表的数据库不能直接执行迁移（缺少的列不会补齐，已有的索引和列会重复创建），
+-- 需要先在开发模式启动一次让 AutoMigrate 同步到当前模型，再用 migrate baseline 标记已执行的版本
+
+CREATE TABLE IF NOT EXISTS `users` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `created_at` datetime(3) NULL COMMENT '创建时间',
+  `updated_at` datetime(3) NULL COMMENT '更新时间',
+  `deleted_at` datetime(3) NULL COMMENT '删除时间',
+  `username` varchar(50) NOT NULL COMMENT '用户名',
+  `email` varchar(100) NOT NULL COMMENT '邮箱',
+  `password` varchar(255) NOT NULL COMMENT '密码',
+  `nickname` varchar(50) COMMENT '昵称',
+  `avatar` varchar(255) COMMENT '头像',
+  `phone` varchar(20) COMMENT '手机号',
+  `status` bigint DEFAULT 1 COMMENT '状态 1-正常 0-禁用',
+  `role` varchar(20) DEFAULT 'member' COMMENT '角色 admin/member/viewer',
+  `last_login_at` datetime(3) NULL COMMENT '最后登录时间',
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_users_username` (`username`),
+  UNIQUE INDEX `idx_users_email` (`email`),
+  INDEX `idx_users_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `tasks` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `created_at` datetime(3) NULL COMMENT '创建时间',
+  `updated_at` datetime(3) NULL COMMENT '更新时间',
+  `deleted_at` datetime(3) NULL COMMENT '删除时间',
+  `title` varchar(200) NOT NULL COMMENT '任务标题',
+  `description` text COMMENT '任务描述',
+  `status` bigint DEFAULT 0 COMMENT '任务状态 0-待处理 1-进行中 2-已完成 3-已取消',
+  `priority` bigint DEFAULT 2 COMMENT '优先级 1-低 2-中 3-高 4-紧急',
+  `start_time` datetime(3) NULL COMMENT '开始时间',
+  `end_time` datetime(3) NULL COMMENT '结束时间',
+  `due_date` datetime(3) NULL COMMENT '截止日期',
+  `user_id` bigint unsigned NOT NULL COMMENT '创建用户ID',
+  PRIMARY KEY (`id`),
+  INDEX `idx_tasks_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `tags` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `created_at` datetime(3) NULL COMMENT '创建时间',
+  `updated_at` datetime(3) NULL COMMENT '更新时间',
+  `deleted_at` datetime(3) NULL COMMENT '删除时间',
+  `name` varchar(50) NOT NULL COMMENT '标签名称',
+  `color` varchar(7) COMMENT '标签颜色',
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_tags_name` (`name`),
+  INDEX `idx_tags_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `task_tags` (
+  `task_id` bigint unsigned NOT NULL,
+  `tag_id` bigint unsigned NOT NULL,
+  PRIMARY KEY (`task_id`, `tag_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
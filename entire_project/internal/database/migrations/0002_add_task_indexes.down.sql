@@ -0,0 +1,3 @@
+DROP INDEX `idx_tasks_due_date` ON `tasks`;
+DROP INDEX `idx_tasks_status` ON `tasks`;
+DROP INDEX `idx_tasks_user_id` ON `tasks`;
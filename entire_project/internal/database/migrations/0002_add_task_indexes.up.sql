@@ -0,0 +1,4 @@
+-- 任务列表最常按用户、状态和截止日期过滤
+CREATE INDEX `idx_tasks_user_id` ON `tasks` (`user_id`);
+CREATE INDEX `idx_tasks_status` ON `tasks` (`status`);
+CREATE INDEX `idx_tasks_due_date` ON `tasks` (`due_date`);
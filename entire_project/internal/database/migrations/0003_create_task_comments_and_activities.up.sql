@@ -0,0 +1,28 @@
+-- 任务评论和任务动态
+-- 二级索引隐含主键列，(task_id) 索引即可满足 task_id = ? AND id < ? ORDER BY id DESC 的游标查询
+
+CREATE TABLE IF NOT EXISTS `task_comments` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `created_at` datetime(3) NULL COMMENT '创建时间',
+  `updated_at` datetime(3) NULL COMMENT '更新时间',
+  `deleted_at` datetime(3) NULL COMMENT '删除时间',
+  `task_id` bigint unsigned NOT NULL COMMENT '任务ID',
+  `user_id` bigint unsigned NOT NULL COMMENT '评论作者ID',
+  `body` text NOT NULL COMMENT '评论内容(Markdown)',
+  PRIMARY KEY (`id`),
+  INDEX `idx_task_comments_task_id` (`task_id`),
+  INDEX `idx_task_comments_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `task_activities` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `created_at` datetime(3) NULL COMMENT '创建时间',
+  `task_id` bigint unsigned NOT NULL COMMENT '任务ID',
+  `user_id` bigint unsigned NOT NULL COMMENT '操作用户ID',
+  `action` varchar(20) NOT NULL COMMENT '动态类型 created/updated/completed',
+  `field` varchar(50) COMMENT '变更字段',
+  `old_value` text COMMENT '旧值',
+  `new_value` text COMMENT '新值',
+  PRIMARY KEY (`id`),
+  INDEX `idx_task_activities_task_id` (`task_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `task_watchers`;
+DROP TABLE IF EXISTS `task_assignees`;
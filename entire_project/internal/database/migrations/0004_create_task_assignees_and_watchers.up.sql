@@ -0,0 +1,16 @@
+-- 任务负责人和关注者：与创建者（tasks.user_id）分开
+-- 主键 (task_id, user_id) 防止重复指派，user_id 索引用于“指派给我的任务”查询
+
+CREATE TABLE IF NOT EXISTS `task_assignees` (
+  `task_id` bigint unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  PRIMARY KEY (`task_id`, `user_id`),
+  INDEX `idx_task_assignees_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `task_watchers` (
+  `task_id` bigint unsigned NOT NULL,
+  `user_id` bigint unsigned NOT NULL,
+  PRIMARY KEY (`task_id`, `user_id`),
+  INDEX `idx_task_watchers_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,10 @@
+-- 回滚前需要先删除项目标签，否则全局标签名称唯一索引可能创建失败
+DROP INDEX `idx_tags_project_id_name` ON `tags`;
+CREATE UNIQUE INDEX `idx_tags_name` ON `tags` (`name`);
+ALTER TABLE `tags` DROP COLUMN `project_id`;
+
+DROP INDEX `idx_tasks_project_id` ON `tasks`;
+ALTER TABLE `tasks` DROP COLUMN `project_id`;
+
+DROP TABLE IF EXISTS `project_members`;
+DROP TABLE IF EXISTS `projects`;
@@ -0,0 +1,33 @@
+-- 项目和项目成员：任务和标签可以归属于项目，项目内的权限由成员角色决定
+-- tasks.project_id 和 tags.project_id 为空表示不属于任何项目（全局任务、全局标签）
+-- 标签名称改为在项目内唯一，全局标签之间仍然唯一（由服务层检查，MySQL 唯一索引不约束 NULL）
+
+CREATE TABLE IF NOT EXISTS `projects` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `created_at` datetime(3) NULL COMMENT '创建时间',
+  `updated_at` datetime(3) NULL COMMENT '更新时间',
+  `deleted_at` datetime(3) NULL COMMENT '删除时间',
+  `name` varchar(100) NOT NULL COMMENT '项目名称',
+  `description` text COMMENT '项目描述',
+  `owner_id` bigint unsigned NOT NULL COMMENT '所有者用户ID',
+  PRIMARY KEY (`id`),
+  INDEX `idx_projects_owner_id` (`owner_id`),
+  INDEX `idx_projects_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS `project_members` (
+  `project_id` bigint unsigned NOT NULL COMMENT '项目ID',
+  `user_id` bigint unsigned NOT NULL COMMENT '用户ID',
+  `role` varchar(20) NOT NULL COMMENT '项目角色 owner/maintainer/member/viewer',
+  `created_at` datetime(3) NULL COMMENT '加入时间',
+  `updated_at` datetime(3) NULL COMMENT '更新时间',
+  PRIMARY KEY (`project_id`, `user_id`),
+  INDEX `idx_project_members_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE `tasks` ADD COLUMN `project_id` bigint unsigned NULL COMMENT '所属项目ID';
+CREATE INDEX `idx_tasks_project_id` ON `tasks` (`project_id`);
+
+ALTER TABLE `tags` ADD COLUMN `project_id` bigint unsigned NULL COMMENT '所属项目ID';
+DROP INDEX `idx_tags_name` ON `tags`;
+CREATE UNIQUE INDEX `idx_tags_project_id_name` ON `tags` (`project_id`, `name`);
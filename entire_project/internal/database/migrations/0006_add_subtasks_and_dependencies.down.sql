@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS `task_dependencies`;
+
+DROP INDEX `idx_tasks_parent_id` ON `tasks`;
+ALTER TABLE `tasks` DROP COLUMN `parent_id`;
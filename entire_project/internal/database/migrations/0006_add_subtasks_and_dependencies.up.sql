@@ -0,0 +1,13 @@
+-- 子任务：parent_id 指向父任务，为空表示顶层任务
+-- 任务依赖：blocker_id 对应的任务结束前，task_id 对应的任务不能开始或完成
+-- 主键 (task_id, blocker_id) 防止重复依赖，blocker_id 索引用于查找被阻塞的后续任务
+
+ALTER TABLE `tasks` ADD COLUMN `parent_id` bigint unsigned NULL COMMENT '父任务ID';
+CREATE INDEX `idx_tasks_parent_id` ON `tasks` (`parent_id`);
+
+CREATE TABLE IF NOT EXISTS `task_dependencies` (
+  `task_id` bigint unsigned NOT NULL,
+  `blocker_id` bigint unsigned NOT NULL,
+  PRIMARY KEY (`task_id`, `blocker_id`),
+  INDEX `idx_task_dependencies_blocker_id` (`blocker_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,4 @@
+DROP INDEX `idx_tags_project_id` ON `tags`;
+DROP INDEX `idx_tags_project_key_name` ON `tags`;
+CREATE UNIQUE INDEX `idx_tags_project_id_name` ON `tags` (`project_id`, `name`);
+ALTER TABLE `tags` DROP COLUMN `project_key`;
@@ -0,0 +1,8 @@
+-- 全局标签名称唯一：MySQL 唯一索引不约束 NULL，(project_id, name) 索引挡不住同名的全局标签
+-- 增加生成列 project_key = COALESCE(project_id, 0)，全局标签统一为 0，再用 (project_key, name) 做唯一索引
+-- 执行前需要先处理已经存在的同名全局标签，否则唯一索引创建失败
+
+ALTER TABLE `tags` ADD COLUMN `project_key` bigint unsigned AS (COALESCE(`project_id`, 0)) STORED COMMENT '所属项目ID（全局标签为0，用于唯一索引）';
+DROP INDEX `idx_tags_project_id_name` ON `tags`;
+CREATE UNIQUE INDEX `idx_tags_project_key_name` ON `tags` (`project_key`, `name`);
+CREATE INDEX `idx_tags_project_id` ON `tags` (`project_id`);
@@ -3,46 +3,45 @@
 package database
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 	"task-management-system/internal/config"
 	"task-management-system/internal/models"
+	"task-management-system/pkg/metrics"
+	"task-management-system/pkg/utils"
 )
 
-// DB 全局数据库连接实例
-var DB *gorm.DB
-
-// InitMySQL 初始化MySQL数据库连接
-// 学习要点：数据库连接初始化，连接池配置，错误处理
-func InitMySQL() error {
-	cfg := &config.GlobalConfig.Database.MySQL
-	
+// Open 打开MySQL数据库连接
+// 学习要点：数据库连接初始化，连接池配置，错误处理；
+// 连接实例由调用方持有并注入到服务中，而不是保存在包级全局变量里
+func Open(cfg *config.MySQLConfig) (*gorm.DB, error) {
 	// 构建DSN（数据源名称）
 	dsn := cfg.GetMySQLDSN()
 	
 	// 配置GORM
 	gormConfig := &gorm.Config{
-		// 配置日志级别
-		Logger: logger.Default.LogMode(logger.Info),
+		// SQL日志输出到 slog，带上请求ID
+		Logger: newGormLogger(),
 		// 禁用外键约束（在代码中维护关系）
 		DisableForeignKeyConstraintWhenMigrating: true,
 	}
 	
 	// 连接数据库
-	var err error
-	DB, err = gorm.Open(mysql.Open(dsn), gormConfig)
+	db, err := gorm.Open(mysql.Open(dsn), gormConfig)
 	if err != nil {
-		return fmt.Errorf("连接MySQL数据库失败: %w", err)
+		return nil, fmt.Errorf("连接MySQL数据库失败: %w", err)
 	}
 	
 	// 获取底层的sql.DB对象来配置连接池
-	sqlDB, err := DB.DB()
+	sqlDB, err := db.DB()
 	if err != nil {
-		return fmt.Errorf("获取数据库实例失败: %w", err)
+		return nil, fmt.Errorf("获取数据库实例失败: %w", err)
 	}
 	
 	// 配置连接池
@@ -53,45 +52,70 @@ func InitMySQL() error {
 	
 	// 测试连接
 	if err := sqlDB.Ping(); err != nil {
-		return fmt.Errorf("数据库连接测试失败: %w", err)
+		return nil, fmt.Errorf("数据库连接测试失败: %w", err)
 	}
 	
-	fmt.Println("✅ MySQL数据库连接成功")
-	return nil
+	// 注册监控指标和链路追踪：每条SQL的耗时、Span 和连接池状态
+	if err := db.Use(&MetricsPlugin{}); err != nil {
+		return nil, fmt.Errorf("注册数据库指标插件失败: %w", err)
+	}
+	if err := db.Use(&TracingPlugin{}); err != nil {
+		return nil, fmt.Errorf("注册数据库链路追踪插件失败: %w", err)
+	}
+	if err := metrics.RegisterDBStats(sqlDB, cfg.DBName); err != nil {
+		return nil, fmt.Errorf("注册连接池指标失败: %w", err)
+	}
+	
+	slog.Info("MySQL数据库连接成功")
+	return db, nil
 }
 
-// AutoMigrate 自动迁移数据库表结构
-// 学习要点：GORM 的自动迁移功能，数据库版本管理
-func AutoMigrate() error {
+// AutoMigrate 根据模型自动同步表结构
+// 学习要点：AutoMigrate 只会新增表和列，不能删除或重命名，也没有版本记录，
+// 因此只在开发模式下使用；其他环境使用 Migrator 执行版本化迁移
+func AutoMigrate(db *gorm.DB) error {
 	// 需要迁移的模型列表
 	models := []interface{}{
 		&models.User{},  // 用户表
 		&models.Task{},  // 任务表
 		&models.Tag{},   // 标签表
+		&models.TaskComment{},  // 任务评论表
+		&models.TaskActivity{}, // 任务动态表
+		&models.TaskDependency{}, // 任务依赖表
+		&models.Project{},       // 项目表
+		&models.ProjectMember{}, // 项目成员表
 	}
 	
 	// 执行自动迁移
-	if err := DB.AutoMigrate(models...); err != nil {
+	if err := db.AutoMigrate(models...); err != nil {
 		return fmt.Errorf("数据库迁移失败: %w", err)
 	}
 	
-	fmt.Println("✅ 数据库表结构迁移完成")
+	slog.Info("数据库表结构迁移完成")
 	return nil
 }
 
+// seedPassword 种子用户的登录密码，只在开发模式使用
+const seedPassword = "secret"
+
 // SeedData 初始化种子数据
-// 学习要点：数据库种子数据的创建，测试数据准备
-func SeedData() error {
+// 学习要点：数据库种子数据的创建，测试数据准备；密码在写入时加密，保证与文档中的密码一致
+func SeedData(db *gorm.DB) error {
 	// 检查是否已存在数据
 	var userCount int64
-	DB.Model(&models.User{}).Count(&userCount)
+	db.Model(&models.User{}).Count(&userCount)
 	if userCount > 0 {
-		fmt.Println("⚠️  数据库已存在数据，跳过种子数据初始化")
+		slog.Info("数据库已存在数据，跳过种子数据初始化")
 		return nil
 	}
 	
+	password, err := utils.HashPassword(seedPassword)
+	if err != nil {
+		return err
+	}
+	
 	// 开始事务
-	tx := DB.Begin()
+	tx := db.Begin()
 	defer func() {
 		if r := recover(); r != nil {
 			tx.Rollback()
@@ -103,16 +127,18 @@ func SeedData() error {
 		{
 			Username: "admin",
 			Email:    "admin@example.com",
-			Password: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy", // 密码: secret
+			Password: password, // 密码: secret
 			Nickname: "管理员",
 			Status:   1,
+			Role:     models.RoleAdmin,
 		},
 		{
 			Username: "testuser",
 			Email:    "test@example.com",
-			Password: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy", // 密码: secret
+			Password: password, // 密码: secret
 			Nickname: "测试用户",
 			Status:   1,
+			Role:     models.RoleMember,
 		},
 	}
 	
@@ -178,17 +204,31 @@ func SeedData() error {
 		return fmt.Errorf("提交事务失败: %w", err)
 	}
 	
-	fmt.Println("✅ 种子数据初始化完成")
+	slog.Info("种子数据初始化完成")
 	return nil
 }
 
+// Ping 检查数据库连接是否可用（就绪探针使用）
+func Ping(ctx context.Context, db *gorm.DB) error {
+	if db == nil {
+		return errors.New("数据库未初始化")
+	}
+	
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	
+	return sqlDB.PingContext(ctx)
+}
+
 // Close 关闭数据库连接
-func Close() error {
-	if DB == nil {
+func Close(db *gorm.DB) error {
+	if db == nil {
 		return nil
 	}
 	
-	sqlDB, err := DB.DB()
+	sqlDB, err := db.DB()
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/middleware"
+	"task-management-system/internal/models"
+	"task-management-system/internal/services"
+)
+
+// AdminHandler 管理员处理器
+// 学习要点：管理员专用接口，统一挂在需要管理员权限的路由组下
+type AdminHandler struct {
+	userService *services.UserService
+	authService *services.AuthService
+	taskService *services.TaskService
+}
+
+// NewAdminHandler 创建管理员处理器实例
+func NewAdminHandler(userService *services.UserService, authService *services.AuthService, taskService *services.TaskService) *AdminHandler {
+	return &AdminHandler{
+		userService: userService,
+		authService: authService,
+		taskService: taskService,
+	}
+}
+
+// DisableUsers 批量禁用用户
+// @Summary 批量禁用用户
+// @Description 禁用指定用户并强制其下线
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.BatchUserIDsRequest true "用户ID列表"
+// @Success 200 {object} models.Response "禁用成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 403 {object} models.Response "权限不足"
+// @Failure 500 {object} models.Response "内部服务器错误"
+// @Router /api/admin/v1/users/disable [post]
+func (h *AdminHandler) DisableUsers(c *gin.Context) {
+	var req models.BatchUserIDsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	// 防止管理员把自己锁在系统外
+	currentUserID, _ := middleware.GetCurrentUserID(c)
+	for _, id := range req.UserIDs {
+		if id == currentUserID {
+			c.Error(apperr.Validation("cannot_disable_self", "不能禁用自己的账号"))
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	if err := h.userService.SetUsersStatus(ctx, req.UserIDs, 0); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// 禁用后立即强制下线
+	for _, id := range req.UserIDs {
+		if err := h.authService.RevokeUserTokens(ctx, id); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse("用户已禁用"))
+}
+
+// EnableUsers 批量启用用户
+// @Summary 批量启用用户
+// @Description 重新启用被禁用的用户
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param body body models.BatchUserIDsRequest true "用户ID列表"
+// @Success 200 {object} models.Response "启用成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 403 {object} models.Response "权限不足"
+// @Failure 500 {object} models.Response "内部服务器错误"
+// @Router /api/admin/v1/users/enable [post]
+func (h *AdminHandler) EnableUsers(c *gin.Context) {
+	var req models.BatchUserIDsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	if err := h.userService.SetUsersStatus(c.Request.Context(), req.UserIDs, 1); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse("用户已启用"))
+}
+
+// ForceLogout 强制用户下线
+// @Summary 强制用户下线
+// @Description 吊销用户当前所有的刷新令牌
+// @Tags 管理员
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户ID"
+// @Success 200 {object} models.Response "操作成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 403 {object} models.Response "权限不足"
+// @Failure 500 {object} models.Response "内部服务器错误"
+// @Router /api/admin/v1/users/{id}/logout [post]
+func (h *AdminHandler) ForceLogout(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(invalidParam("用户ID格式错误"))
+		return
+	}
+
+	if err := h.authService.RevokeUserTokens(c.Request.Context(), uint(id)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse("用户已强制下线"))
+}
+
+// UpdateUserRole 修改用户角色
+// @Summary 修改用户角色
+// @Description 设置用户角色（admin/member/viewer），修改后强制用户重新登录以获取新权限
+// @Tags 管理员
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户ID"
+// @Param body body models.UpdateRoleRequest true "角色"
+// @Success 200 {object} models.Response{data=models.UserResponse} "修改成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 403 {object} models.Response "权限不足"
+// @Failure 500 {object} models.Response "内部服务器错误"
+// @Router /api/admin/v1/users/{id}/role [put]
+func (h *AdminHandler) UpdateUserRole(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.Error(invalidParam("用户ID格式错误"))
+		return
+	}
+
+	var req models.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	// 防止管理员误把自己降级后失去管理权限
+	if currentUserID, _ := middleware.GetCurrentUserID(c); currentUserID == uint(id) && req.Role != models.RoleAdmin {
+		c.Error(apperr.Validation("cannot_demote_self", "不能取消自己的管理员角色"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := h.userService.UpdateRole(ctx, uint(id), req.Role)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// 角色保存在令牌中，修改后需要重新登录才能生效
+	if err := h.authService.RevokeUserTokens(ctx, uint(id)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(user.ToResponse()))
+}
+
+// DeleteUser 删除用户
+// @Summary 删除用户
+// @Description 软删除用户账户及其所有任务，并强制用户下线
+// @Tags 管理员
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "用户ID"
+// @Success 200 {object} models.Response "删除成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 403 {object} models.Response "权限不足"
+// @Failure 404 {object} models.Response "用户不存在"
+// @Failure 500 {object} models.Response "内部服务器错误"
+// @Router /api/admin/v1/users/{id} [delete]
+func (h *AdminHandler) DeleteUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("用户ID格式错误"))
+		return
+	}
+
+	// 防止管理员删除自己的账号
+	if currentUserID, _ := middleware.GetCurrentUserID(c); currentUserID == uint(id) {
+		c.Error(apperr.Validation("cannot_delete_self", "不能删除自己的账号"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.userService.DeleteUser(ctx, uint(id)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// 删除后立即强制下线，已签发的令牌不能继续使用
+	if err := h.authService.RevokeUserTokens(ctx, uint(id)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse("用户删除成功"))
+}
+
+// GetUserStats 获取用户统计
+// @Summary 获取用户统计
+// @Description 按状态、角色统计用户数，以及最近N天的活跃用户数
+// @Tags 管理员
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "活跃用户统计天数(1-90)" default(7)
+// @Success 200 {object} models.Response{data=models.UserStats} "获取成功"
+// @Failure 403 {object} models.Response "权限不足"
+// @Failure 500 {object} models.Response "内部服务器错误"
+// @Router /api/admin/v1/users/stats [get]
+func (h *AdminHandler) GetUserStats(c *gin.Context) {
+	days := parseStatsDays(c)
+
+	stats, err := h.userService.GetUserStats(c.Request.Context(), days)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(stats))
+}
+
+// GetTaskStats 获取任务统计
+// @Summary 获取任务统计
+// @Description 按状态、优先级统计任务数，过期任务数以及最近N天每日完成数量
+// @Tags 管理员
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "完成趋势天数(1-90)" default(7)
+// @Param project_id query int false "只统计指定项目的任务"
+// @Success 200 {object} models.Response{data=models.TaskStats} "获取成功"
+// @Failure 403 {object} models.Response "权限不足"
+// @Failure 500 {object} models.Response "内部服务器错误"
+// @Router /api/admin/v1/tasks/stats [get]
+func (h *AdminHandler) GetTaskStats(c *gin.Context) {
+	days := parseStatsDays(c)
+
+	var projectID *uint
+	if value := c.Query("project_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.Error(invalidParam("项目ID格式错误"))
+			return
+		}
+		projectID = new(uint)
+		*projectID = uint(id)
+	}
+
+	stats, err := h.taskService.GetTaskStats(c.Request.Context(), days, projectID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(stats))
+}
+
+// parseStatsDays 解析统计天数参数，限制在1-90天之间
+func parseStatsDays(c *gin.Context) int {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days <= 0 {
+		return 7
+	}
+	if days > 90 {
+		return 90
+	}
+	return days
+}
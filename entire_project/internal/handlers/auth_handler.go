@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"task-management-system/internal/models"
+	"task-management-system/internal/services"
+)
+
+// AuthHandler 认证处理器
+// 学习要点：登录、刷新令牌与退出登录接口
+type AuthHandler struct {
+	authService *services.AuthService
+}
+
+// NewAuthHandler 创建认证处理器实例
+func NewAuthHandler(authService *services.AuthService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+	}
+}
+
+// Login 用户登录
+// @Summary 用户登录
+// @Description 使用用户名和密码登录，返回访问令牌和刷新令牌；连续失败次数过多时临时锁定
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param credentials body models.LoginRequest true "登录信息"
+// @Success 200 {object} models.Response "登录成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 401 {object} models.Response "用户名或密码错误"
+// @Failure 403 {object} models.Response "用户已被禁用"
+// @Failure 429 {object} models.Response "登录失败次数过多"
+// @Failure 500 {object} models.Response "内部服务器错误"
+// @Router /api/v1/auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	tokens, user, err := h.authService.Login(c.Request.Context(), &req, c.ClientIP())
+	if err != nil {
+		h.renderAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{
+		"token": tokens,
+		"user":  user.ToResponse(),
+	}))
+}
+
+// Refresh 刷新令牌
+// @Summary 刷新令牌
+// @Description 使用刷新令牌换取新的令牌对，旧的刷新令牌随即失效
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param body body models.RefreshTokenRequest true "刷新令牌"
+// @Success 200 {object} models.Response "刷新成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 401 {object} models.Response "刷新令牌无效"
+// @Failure 500 {object} models.Response "内部服务器错误"
+// @Router /api/v1/auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	tokens, err := h.authService.RefreshToken(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		h.renderAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(gin.H{"token": tokens}))
+}
+
+// Logout 退出登录
+// @Summary 退出登录
+// @Description 吊销刷新令牌
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param body body models.RefreshTokenRequest true "刷新令牌"
+// @Success 200 {object} models.Response "退出成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 401 {object} models.Response "刷新令牌无效"
+// @Failure 500 {object} models.Response "内部服务器错误"
+// @Router /api/v1/auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	if err := h.authService.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		h.renderAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse("退出登录成功"))
+}
+
+// renderAuthError 记录认证错误，账号被锁定时额外设置 Retry-After 头
+func (h *AuthHandler) renderAuthError(c *gin.Context, err error) {
+	// 被锁定时通过 Retry-After 头告诉客户端多久后可以重试
+	var lockedErr *services.AccountLockedError
+	if errors.As(err, &lockedErr) {
+		c.Header("Retry-After", strconv.Itoa(int(lockedErr.RetryAfter.Seconds()+0.5)))
+	}
+	c.Error(err)
+}
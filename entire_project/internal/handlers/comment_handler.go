@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"task-management-system/internal/middleware"
+	"task-management-system/internal/models"
+	"task-management-system/internal/services"
+)
+
+// CommentHandler 任务评论处理器
+// 学习要点：嵌套资源的处理，评论ID和任务ID都从路径中解析
+type CommentHandler struct {
+	commentService *services.CommentService
+}
+
+// NewCommentHandler 创建任务评论处理器实例
+func NewCommentHandler(commentService *services.CommentService) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+// CreateComment 发表评论
+// @Summary 发表评论
+// @Description 在任务下发表评论，内容为 Markdown；项目任务需要拥有项目的任务写权限
+// @Tags 任务评论
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Param comment body models.CommentCreateRequest true "评论内容"
+// @Success 200 {object} models.Response{data=models.TaskComment} "发表成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 403 {object} models.Response "没有权限访问此项目"
+// @Failure 404 {object} models.Response "任务不存在"
+// @Router /api/v1/tasks/{id}/comments [post]
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("任务ID格式错误"))
+		return
+	}
+
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
+		return
+	}
+
+	var req models.CommentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	comment, err := h.commentService.CreateComment(c.Request.Context(), uint(taskID), userID, middleware.GetCurrentRole(c), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(comment))
+}
+
+// ListComments 获取任务评论列表
+// @Summary 获取任务评论列表
+// @Description 游标分页获取任务评论，最新的评论在前；项目任务需要是项目成员
+// @Tags 任务评论
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Param cursor query string false "上一页返回的 next_cursor"
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} models.Response{data=models.CursorPage} "获取成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 401 {object} models.Response "未登录"
+// @Failure 403 {object} models.Response "没有权限访问此项目"
+// @Failure 404 {object} models.Response "任务不存在"
+// @Router /api/v1/tasks/{id}/comments [get]
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("任务ID格式错误"))
+		return
+	}
+
+	var query models.CursorQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(invalidParam("查询参数格式错误"))
+		return
+	}
+
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
+		return
+	}
+
+	page, err := h.commentService.ListComments(c.Request.Context(), uint(taskID), userID, middleware.GetCurrentRole(c), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(page))
+}
+
+// UpdateComment 编辑评论
+// @Summary 编辑评论
+// @Description 编辑自己发表的评论
+// @Tags 任务评论
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Param comment_id path int true "评论ID"
+// @Param comment body models.CommentUpdateRequest true "评论内容"
+// @Success 200 {object} models.Response{data=models.TaskComment} "编辑成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 403 {object} models.Response "不是评论作者"
+// @Failure 404 {object} models.Response "评论不存在"
+// @Router /api/v1/tasks/{id}/comments/{comment_id} [put]
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	taskID, commentID, ok := parseCommentPath(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
+		return
+	}
+
+	var req models.CommentUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	comment, err := h.commentService.UpdateComment(c.Request.Context(), taskID, commentID, userID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(comment))
+}
+
+// DeleteComment 删除评论
+// @Summary 删除评论
+// @Description 删除自己发表的评论，管理员可以删除任何评论
+// @Tags 任务评论
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Param comment_id path int true "评论ID"
+// @Success 200 {object} models.Response "删除成功"
+// @Failure 403 {object} models.Response "不是评论作者"
+// @Failure 404 {object} models.Response "评论不存在"
+// @Router /api/v1/tasks/{id}/comments/{comment_id} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	taskID, commentID, ok := parseCommentPath(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
+		return
+	}
+
+	err := h.commentService.DeleteComment(c.Request.Context(), taskID, commentID, userID, middleware.GetCurrentRole(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse("评论删除成功"))
+}
+
+// parseCommentPath 解析路径中的任务ID和评论ID，格式错误时记录错误并返回 false
+func parseCommentPath(c *gin.Context) (uint, uint, bool) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("任务ID格式错误"))
+		return 0, 0, false
+	}
+	commentID, err := strconv.ParseUint(c.Param("comment_id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("评论ID格式错误"))
+		return 0, 0, false
+	}
+	return uint(taskID), uint(commentID), true
+}
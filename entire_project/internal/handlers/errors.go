@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"task-management-system/internal/apperr"
+)
+
+// 处理器层的通用错误
+// 学习要点：处理器只负责 c.Error(err) 记录错误并返回，
+// 状态码和响应格式由 middleware.ErrorMiddleware 统一决定
+var errNotLoggedIn = apperr.Unauthorized("unauthenticated", "请先登录")
+
+// invalidParam 路径或查询参数格式错误
+func invalidParam(message string) error {
+	return apperr.Validation("invalid_param", message)
+}
+
+// bindError 请求体或查询参数绑定失败
+func bindError(err error) error {
+	return apperr.Wrap(err, apperr.ErrValidation, "invalid_request", "请求参数错误")
+}
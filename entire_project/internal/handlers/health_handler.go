@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"task-management-system/internal/health"
+)
+
+// HealthHandler 健康检查处理器
+// 学习要点：探针接口直接返回JSON而不使用统一响应格式，便于 k8s 和负载均衡只看状态码
+type HealthHandler struct {
+	checker   *health.Checker
+	buildInfo map[string]string
+}
+
+// NewHealthHandler 创建健康检查处理器实例
+func NewHealthHandler(checker *health.Checker, buildInfo map[string]string) *HealthHandler {
+	return &HealthHandler{
+		checker:   checker,
+		buildInfo: buildInfo,
+	}
+}
+
+// Livez 存活探针
+// @Summary 存活探针
+// @Description 进程能响应请求即返回200，不检查外部依赖，避免数据库故障时 k8s 反复重启所有实例
+// @Tags 健康检查
+// @Produce json
+// @Success 200 {object} map[string]string "存活"
+// @Router /livez [get]
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz 就绪探针
+// @Summary 就绪探针
+// @Description 检查 MySQL 和缓存是否可用，返回各依赖的状态和耗时；关闭期间返回503
+// @Tags 健康检查
+// @Produce json
+// @Success 200 {object} health.Report "就绪"
+// @Failure 503 {object} health.Report "依赖不可用或正在关闭"
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.checker.Check(c.Request.Context())
+
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+// Version 构建信息
+// @Summary 构建信息
+// @Description 返回版本号、构建时间和Git提交
+// @Tags 健康检查
+// @Produce json
+// @Success 200 {object} map[string]string "构建信息"
+// @Router /version [get]
+func (h *HealthHandler) Version(c *gin.Context) {
+	c.JSON(http.StatusOK, h.buildInfo)
+}
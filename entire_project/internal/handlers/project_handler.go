@@ -0,0 +1,463 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"task-management-system/internal/middleware"
+	"task-management-system/internal/models"
+	"task-management-system/internal/services"
+)
+
+// ProjectHandler 项目处理器
+// 学习要点：项目下的标签和统计复用标签服务和任务服务，权限统一由项目服务判断
+type ProjectHandler struct {
+	projectService *services.ProjectService
+	tagService     *services.TagService
+	taskService    *services.TaskService
+}
+
+// NewProjectHandler 创建项目处理器实例
+func NewProjectHandler(projectService *services.ProjectService, tagService *services.TagService, taskService *services.TaskService) *ProjectHandler {
+	return &ProjectHandler{
+		projectService: projectService,
+		tagService:     tagService,
+		taskService:    taskService,
+	}
+}
+
+// CreateProject 创建项目
+// @Summary 创建项目
+// @Description 创建项目，当前用户成为项目所有者
+// @Tags 项目管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project body models.ProjectCreateRequest true "项目信息"
+// @Success 200 {object} models.Response{data=models.Project} "创建成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Router /api/v1/projects [post]
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	var req models.ProjectCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
+		return
+	}
+
+	project, err := h.projectService.CreateProject(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(project))
+}
+
+// ListProjects 获取项目列表
+// @Summary 获取项目列表
+// @Description 分页获取当前用户参与的项目，管理员获取所有项目
+// @Tags 项目管理
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} models.Response{data=models.PageResult} "获取成功"
+// @Router /api/v1/projects [get]
+func (h *ProjectHandler) ListProjects(c *gin.Context) {
+	page, pageSize := parsePage(c)
+
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
+		return
+	}
+
+	result, err := h.projectService.ListProjects(c.Request.Context(), userID, middleware.GetCurrentRole(c), page, pageSize)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(result))
+}
+
+// GetProject 获取项目详情
+// @Summary 获取项目详情
+// @Description 获取项目信息和成员列表（需要是项目成员）
+// @Tags 项目管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "项目ID"
+// @Success 200 {object} models.Response{data=models.Project} "获取成功"
+// @Failure 403 {object} models.Response "不是项目成员"
+// @Failure 404 {object} models.Response "项目不存在"
+// @Router /api/v1/projects/{id} [get]
+func (h *ProjectHandler) GetProject(c *gin.Context) {
+	id, userID, ok := parseProjectRequest(c)
+	if !ok {
+		return
+	}
+
+	project, err := h.projectService.GetProject(c.Request.Context(), id, userID, middleware.GetCurrentRole(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(project))
+}
+
+// UpdateProject 更新项目
+// @Summary 更新项目
+// @Description 更新项目名称和描述（需要项目管理权限）
+// @Tags 项目管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "项目ID"
+// @Param project body models.ProjectUpdateRequest true "项目信息"
+// @Success 200 {object} models.Response{data=models.Project} "更新成功"
+// @Failure 403 {object} models.Response "权限不足"
+// @Failure 404 {object} models.Response "项目不存在"
+// @Router /api/v1/projects/{id} [put]
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	id, userID, ok := parseProjectRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.ProjectUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	project, err := h.projectService.UpdateProject(c.Request.Context(), id, userID, middleware.GetCurrentRole(c), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(project))
+}
+
+// DeleteProject 删除项目
+// @Summary 删除项目
+// @Description 删除没有任务的项目，项目标签一并删除（仅项目所有者）
+// @Tags 项目管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "项目ID"
+// @Success 200 {object} models.Response "删除成功"
+// @Failure 403 {object} models.Response "权限不足"
+// @Failure 404 {object} models.Response "项目不存在"
+// @Failure 409 {object} models.Response "项目中还有任务"
+// @Router /api/v1/projects/{id} [delete]
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	id, userID, ok := parseProjectRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.projectService.DeleteProject(c.Request.Context(), id, userID, middleware.GetCurrentRole(c)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse("项目删除成功"))
+}
+
+// ListMembers 获取项目成员
+// @Summary 获取项目成员
+// @Tags 项目管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "项目ID"
+// @Success 200 {object} models.Response{data=[]models.ProjectMember} "获取成功"
+// @Failure 403 {object} models.Response "不是项目成员"
+// @Router /api/v1/projects/{id}/members [get]
+func (h *ProjectHandler) ListMembers(c *gin.Context) {
+	id, userID, ok := parseProjectRequest(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.projectService.ListMembers(c.Request.Context(), id, userID, middleware.GetCurrentRole(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(members))
+}
+
+// AddMember 添加项目成员
+// @Summary 添加项目成员
+// @Description 添加成员并指定项目角色（需要项目管理权限）
+// @Tags 项目管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "项目ID"
+// @Param member body models.ProjectMemberRequest true "成员信息"
+// @Success 200 {object} models.Response{data=models.ProjectMember} "添加成功"
+// @Failure 403 {object} models.Response "权限不足"
+// @Failure 409 {object} models.Response "已经是项目成员"
+// @Router /api/v1/projects/{id}/members [post]
+func (h *ProjectHandler) AddMember(c *gin.Context) {
+	id, userID, ok := parseProjectRequest(c)
+	if !ok {
+		return
+	}
+
+	var req models.ProjectMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	member, err := h.projectService.AddMember(c.Request.Context(), id, userID, middleware.GetCurrentRole(c), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(member))
+}
+
+// UpdateMember 修改项目成员角色
+// @Summary 修改项目成员角色
+// @Tags 项目管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "项目ID"
+// @Param user_id path int true "成员用户ID"
+// @Param member body models.ProjectMemberUpdateRequest true "项目角色"
+// @Success 200 {object} models.Response "修改成功"
+// @Failure 400 {object} models.Response "不能修改项目所有者"
+// @Failure 403 {object} models.Response "权限不足"
+// @Router /api/v1/projects/{id}/members/{user_id} [put]
+func (h *ProjectHandler) UpdateMember(c *gin.Context) {
+	id, userID, ok := parseProjectRequest(c)
+	if !ok {
+		return
+	}
+	memberID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("用户ID格式错误"))
+		return
+	}
+
+	var req models.ProjectMemberUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	err = h.projectService.UpdateMemberRole(c.Request.Context(), id, userID, middleware.GetCurrentRole(c), uint(memberID), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse("成员角色修改成功"))
+}
+
+// RemoveMember 移除项目成员
+// @Summary 移除项目成员
+// @Description 移除项目成员（需要项目管理权限），成员也可以移除自己退出项目
+// @Tags 项目管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "项目ID"
+// @Param user_id path int true "成员用户ID"
+// @Success 200 {object} models.Response "移除成功"
+// @Failure 400 {object} models.Response "不能移除项目所有者"
+// @Failure 403 {object} models.Response "权限不足"
+// @Router /api/v1/projects/{id}/members/{user_id} [delete]
+func (h *ProjectHandler) RemoveMember(c *gin.Context) {
+	id, userID, ok := parseProjectRequest(c)
+	if !ok {
+		return
+	}
+	memberID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("用户ID格式错误"))
+		return
+	}
+
+	err = h.projectService.RemoveMember(c.Request.Context(), id, userID, middleware.GetCurrentRole(c), uint(memberID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse("成员移除成功"))
+}
+
+// ListTags 获取项目标签
+// @Summary 获取项目标签
+// @Tags 项目管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "项目ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} models.Response{data=models.PageResult} "获取成功"
+// @Failure 403 {object} models.Response "不是项目成员"
+// @Router /api/v1/projects/{id}/tags [get]
+func (h *ProjectHandler) ListTags(c *gin.Context) {
+	id, ok := h.authorize(c, models.PermissionTaskRead)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c)
+
+	result, err := h.tagService.GetTagList(c.Request.Context(), &id, page, pageSize)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(result))
+}
+
+// CreateTag 创建项目标签
+// @Summary 创建项目标签
+// @Description 创建只在本项目中使用的标签，名称在项目内唯一（需要项目写权限）
+// @Tags 项目管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "项目ID"
+// @Param tag body models.TagCreateRequest true "标签信息"
+// @Success 200 {object} models.Response{data=models.TagResponse} "创建成功"
+// @Failure 403 {object} models.Response "权限不足"
+// @Failure 409 {object} models.Response "标签名称已存在"
+// @Router /api/v1/projects/{id}/tags [post]
+func (h *ProjectHandler) CreateTag(c *gin.Context) {
+	id, ok := h.authorize(c, models.PermissionTaskWrite)
+	if !ok {
+		return
+	}
+
+	var req models.TagCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	tag, err := h.tagService.CreateTag(c.Request.Context(), &id, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(tag))
+}
+
+// DeleteTag 删除项目标签
+// @Summary 删除项目标签
+// @Description 删除项目标签并解除与任务的关联（需要项目管理权限）
+// @Tags 项目管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "项目ID"
+// @Param tag_id path int true "标签ID"
+// @Success 200 {object} models.Response "删除成功"
+// @Failure 403 {object} models.Response "权限不足"
+// @Failure 404 {object} models.Response "标签不存在"
+// @Router /api/v1/projects/{id}/tags/{tag_id} [delete]
+func (h *ProjectHandler) DeleteTag(c *gin.Context) {
+	id, ok := h.authorize(c, models.PermissionProjectManage)
+	if !ok {
+		return
+	}
+	tagID, err := strconv.ParseUint(c.Param("tag_id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("标签ID格式错误"))
+		return
+	}
+
+	if err := h.tagService.DeleteProjectTag(c.Request.Context(), id, uint(tagID)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse("标签删除成功"))
+}
+
+// GetStats 获取项目任务统计
+// @Summary 获取项目任务统计
+// @Description 按状态、优先级统计项目中的任务，过期任务数以及最近N天每日完成数量
+// @Tags 项目管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "项目ID"
+// @Param days query int false "完成趋势天数(1-90)" default(7)
+// @Success 200 {object} models.Response{data=models.TaskStats} "获取成功"
+// @Failure 403 {object} models.Response "不是项目成员"
+// @Router /api/v1/projects/{id}/stats [get]
+func (h *ProjectHandler) GetStats(c *gin.Context) {
+	id, ok := h.authorize(c, models.PermissionTaskRead)
+	if !ok {
+		return
+	}
+
+	stats, err := h.taskService.GetTaskStats(c.Request.Context(), parseStatsDays(c), &id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(stats))
+}
+
+// authorize 解析项目ID并校验当前用户的项目权限，失败时记录错误并返回 false
+func (h *ProjectHandler) authorize(c *gin.Context, permission models.Permission) (uint, bool) {
+	id, userID, ok := parseProjectRequest(c)
+	if !ok {
+		return 0, false
+	}
+	if err := h.projectService.Authorize(c.Request.Context(), id, userID, middleware.GetCurrentRole(c), permission); err != nil {
+		c.Error(err)
+		return 0, false
+	}
+	return id, true
+}
+
+// parseProjectRequest 解析路径中的项目ID和当前登录用户，失败时记录错误并返回 false
+func parseProjectRequest(c *gin.Context) (uint, uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("项目ID格式错误"))
+		return 0, 0, false
+	}
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
+		return 0, 0, false
+	}
+	return uint(id), userID, true
+}
+
+// parsePage 解析分页参数，默认每页20条，最多100条
+func parsePage(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	return page, pageSize
+}
@@ -2,33 +2,77 @@ package handlers
 
 import (
 	"github.com/gin-gonic/gin"
+	"task-management-system/internal/app"
+	"task-management-system/internal/config"
 	"task-management-system/internal/middleware"
+	"task-management-system/internal/models"
+	"task-management-system/pkg/metrics"
 )
 
 // SetupRoutes 设置路由
-// 学习要点：路由组织，中间件应用，RESTful API设计
-func SetupRoutes() *gin.Engine {
+// 学习要点：路由组织，中间件应用，RESTful API设计；
+// 处理器所需的服务全部从应用容器中获取
+func SetupRoutes(container *app.Container) *gin.Engine {
 	// 创建Gin路由器
 	r := gin.New()
 	
 	// 添加中间件
 	// 学习要点：中间件的使用顺序很重要
-	r.Use(gin.Logger())                          // 日志中间件
+	r.Use(middleware.TracingMiddleware())        // 链路追踪中间件（最先执行，延续上游的 traceparent）
+	r.Use(middleware.RequestIDMiddleware())      // 请求ID中间件（后续日志都带 request_id）
+	r.Use(middleware.LoggerMiddleware())         // 访问日志中间件
+	r.Use(middleware.MetricsMiddleware())        // 请求指标中间件
 	r.Use(gin.Recovery())                        // 恢复中间件（防止panic导致程序崩溃）
-	r.Use(middleware.CorsMiddleware())           // CORS中间件
-	r.Use(middleware.RequestIDMiddleware())      // 请求ID中间件
+	r.Use(middleware.ErrorMiddleware())          // 统一错误处理中间件
+	r.Use(middleware.CorsMiddleware(&container.Config.CORS)) // CORS中间件（来源白名单）
+	if container.Config.SecurityHeaders.Enabled {
+		r.Use(middleware.SecurityHeadersMiddleware(&container.Config.SecurityHeaders)) // 安全响应头中间件
+	}
 	
 	// 健康检查端点
+	// 学习要点：/livez 和 /readyz 分别对应 k8s 的存活探针和就绪探针，/health 保留给旧的调用方
+	healthHandler := NewHealthHandler(container.Health, container.BuildInfo)
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
 			"status":  "ok",
 			"message": "任务管理系统运行正常",
 		})
 	})
+	r.GET("/livez", healthHandler.Livez)     // 存活探针
+	r.GET("/readyz", healthHandler.Readyz)   // 就绪探针
+	r.GET("/version", healthHandler.Version) // 构建信息
+	
+	// Prometheus 指标端点
+	// 学习要点：生产环境应只允许监控系统访问（网络策略或反向代理限制）
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+	
+	// 创建令牌管理器和认证中间件
+	// 学习要点：认证中间件只挂在需要登录的路由上
+	authRequired := middleware.AuthMiddleware(container.TokenManager, container.AuthService)
+	canWriteTask := middleware.RequirePermission(models.PermissionTaskWrite)
+	canManageTags := middleware.RequirePermission(models.PermissionTagManage)
+	
+	// 请求超时中间件：超时后取消数据库和缓存操作并返回504
+	requestTimeout := middleware.TimeoutMiddleware(container.Config.Server.GetRequestTimeout())
+	
+	// 限流中间件：每个路由组单独计数，未启用时不挂载
+	// 学习要点：可选认证先识别出当前用户，已登录按用户限流，匿名请求按IP限流
+	rateLimitCfg := &container.Config.RateLimit
+	rateLimit := func(group string, rule config.RateLimitRule) gin.HandlerFunc {
+		if !rateLimitCfg.Enabled {
+			return func(c *gin.Context) { c.Next() }
+		}
+		return middleware.RateLimitMiddleware(container.RateLimiter, group, rule)
+	}
 	
 	// 创建处理器实例
-	userHandler := NewUserHandler()
-	taskHandler := NewTaskHandler()
+	userHandler := NewUserHandler(container.UserService)
+	taskHandler := NewTaskHandler(container.TaskService, container.ProjectService)
+	authHandler := NewAuthHandler(container.AuthService)
+	adminHandler := NewAdminHandler(container.UserService, container.AuthService, container.TaskService)
+	tagHandler := NewTagHandler(container.TagService)
+	commentHandler := NewCommentHandler(container.CommentService)
+	projectHandler := NewProjectHandler(container.ProjectService, container.TagService, container.TaskService)
 	
 	// API路由组
 	// 学习要点：路由组的使用，版本控制
@@ -36,48 +80,96 @@ func SetupRoutes() *gin.Engine {
 	{
 		// v1版本路由
 		v1 := api.Group("/v1")
+		v1.Use(requestTimeout, middleware.OptionalAuthMiddleware(container.TokenManager, container.AuthService))
 		{
+			// 认证相关路由
+			authGroup := v1.Group("/auth")
+			{
+				authGroup.POST("/login", authHandler.Login)                     // 登录
+				authGroup.POST("/refresh", authHandler.Refresh)                 // 刷新令牌
+				authGroup.POST("/logout", authHandler.Logout)                   // 退出登录
+			}
+			
 			// 用户相关路由
 			// 学习要点：RESTful风格的路由设计
 			users := v1.Group("/users")
+			users.Use(rateLimit("user", rateLimitCfg.GetUser()))
 			{
 				users.POST("", userHandler.CreateUser)                           // 创建用户
 				users.GET("", userHandler.GetUserList)                          // 获取用户列表
 				users.GET("/:id", userHandler.GetUser)                          // 获取单个用户
-				users.PUT("/:id", userHandler.UpdateUser)                       // 更新用户
-				users.DELETE("/:id", userHandler.DeleteUser)                    // 删除用户
+				users.PUT("/:id", authRequired, userHandler.UpdateUser)         // 更新用户（本人或有用户管理权限）
+				users.PUT("/:id/password", authRequired, userHandler.ChangePassword) // 修改密码（需登录）
 				users.GET("/username/:username", userHandler.GetUserByUsername) // 根据用户名获取用户
-				users.POST("/:id/login", userHandler.UpdateLastLoginTime)       // 更新登录时间
 				
 				// 用户相关的任务路由
 				// 学习要点：嵌套资源的路由设计
-				users.GET("/:user_id/tasks", taskHandler.GetUserTasks)          // 获取用户任务列表
-				users.GET("/:user_id/tasks/stats", taskHandler.GetUserTaskStats) // 获取用户任务统计
+				// 注意：gin 要求同一位置的路径参数同名，因此这里也使用 :id
+				users.GET("/:id/tasks", taskHandler.GetUserTasks)               // 获取用户任务列表
+				users.GET("/:id/tasks/stats", taskHandler.GetUserTaskStats)     // 获取用户任务统计
 			}
 			
 			// 任务相关路由
 			tasks := v1.Group("/tasks")
+			tasks.Use(rateLimit("task", rateLimitCfg.GetTask()))
 			{
-				tasks.POST("", taskHandler.CreateTask)                          // 创建任务
-				tasks.GET("", taskHandler.QueryTasks)                           // 查询任务列表
-				tasks.GET("/:id", taskHandler.GetTask)                          // 获取任务详情
-				tasks.PUT("/:id", taskHandler.UpdateTask)                       // 更新任务
-				tasks.DELETE("/:id", taskHandler.DeleteTask)                    // 删除任务
-				tasks.POST("/:id/complete", taskHandler.MarkTaskComplete)       // 标记任务完成
+				tasks.POST("", authRequired, canWriteTask, taskHandler.CreateTask)       // 创建任务（需写权限）
+				tasks.GET("", taskHandler.QueryTasks)                                    // 查询任务列表
+				tasks.GET("/:id", taskHandler.GetTask)                                   // 获取任务详情
+				tasks.PUT("/:id", authRequired, canWriteTask, taskHandler.UpdateTask)    // 更新任务（需写权限）
+				tasks.DELETE("/:id", authRequired, canWriteTask, taskHandler.DeleteTask) // 删除任务（需写权限）
+				tasks.POST("/:id/complete", authRequired, canWriteTask, taskHandler.MarkTaskComplete) // 标记任务完成（需写权限）
+				tasks.GET("/:id/activity", authRequired, taskHandler.ListTaskActivities) // 获取任务动态（项目任务需是项目成员）
+				
+				// 负责人和关注者：创建者和负责人可以指派，关注只需要登录（只读角色也可以关注）
+				tasks.POST("/:id/assignees", authRequired, canWriteTask, taskHandler.AssignTask)                // 指派负责人
+				tasks.DELETE("/:id/assignees/:user_id", authRequired, canWriteTask, taskHandler.UnassignTask)   // 移除负责人
+				tasks.POST("/:id/dependencies", authRequired, canWriteTask, taskHandler.AddDependency)          // 添加前置任务
+				tasks.DELETE("/:id/dependencies/:blocker_id", authRequired, canWriteTask, taskHandler.RemoveDependency) // 移除前置任务
+				tasks.POST("/:id/watch", authRequired, taskHandler.WatchTask)                                   // 关注任务
+				tasks.DELETE("/:id/watch", authRequired, taskHandler.UnwatchTask)                               // 取消关注
+				
+				// 任务评论：查看和发表在服务层校验项目权限，编辑和删除在服务层校验是否为作者
+				tasks.GET("/:id/comments", authRequired, commentHandler.ListComments)                            // 获取评论列表
+				tasks.POST("/:id/comments", authRequired, canWriteTask, commentHandler.CreateComment)             // 发表评论
+				tasks.PUT("/:id/comments/:comment_id", authRequired, canWriteTask, commentHandler.UpdateComment)  // 编辑自己的评论
+				tasks.DELETE("/:id/comments/:comment_id", authRequired, commentHandler.DeleteComment)             // 删除自己的评论（管理员可删除任意评论）
+			}
+			
+			// 项目相关路由
+			// 学习要点：项目接口都需要登录，项目内的权限由项目角色决定，在服务层校验
+			projects := v1.Group("/projects")
+			projects.Use(authRequired, rateLimit("task", rateLimitCfg.GetTask())) // 与任务接口共享限额
+			{
+				projects.POST("", canWriteTask, projectHandler.CreateProject)           // 创建项目
+				projects.GET("", projectHandler.ListProjects)                           // 获取参与的项目列表
+				projects.GET("/:id", projectHandler.GetProject)                         // 获取项目详情
+				projects.PUT("/:id", projectHandler.UpdateProject)                      // 更新项目
+				projects.DELETE("/:id", projectHandler.DeleteProject)                   // 删除项目
+				projects.GET("/:id/members", projectHandler.ListMembers)                // 获取项目成员
+				projects.POST("/:id/members", projectHandler.AddMember)                 // 添加项目成员
+				projects.PUT("/:id/members/:user_id", projectHandler.UpdateMember)      // 修改成员角色
+				projects.DELETE("/:id/members/:user_id", projectHandler.RemoveMember)   // 移除成员（或退出项目）
+				projects.GET("/:id/tags", projectHandler.ListTags)                      // 获取项目标签
+				projects.POST("/:id/tags", projectHandler.CreateTag)                    // 创建项目标签
+				projects.DELETE("/:id/tags/:tag_id", projectHandler.DeleteTag)          // 删除项目标签
+				projects.GET("/:id/stats", projectHandler.GetStats)                     // 项目任务统计
 			}
 			
 			// 标签相关路由
 			tags := v1.Group("/tags")
+			tags.Use(rateLimit("task", rateLimitCfg.GetTask())) // 与任务接口共享限额
 			{
-				// 标签相关的任务路由
-				tags.GET("/:tag_id/tasks", taskHandler.GetTasksByTag)           // 根据标签获取任务
+				// 标签的CRUD操作
+				// 学习要点：成员可以创建标签，修改和删除全局标签需要管理权限
+				tags.POST("", authRequired, canWriteTask, tagHandler.CreateTag)  // 创建标签
+				tags.GET("", tagHandler.GetTagList)                              // 获取标签列表
+				tags.GET("/:id", tagHandler.GetTag)                              // 获取标签详情
+				tags.PUT("/:id", authRequired, canManageTags, tagHandler.UpdateTag)    // 更新标签
+				tags.DELETE("/:id", authRequired, canManageTags, tagHandler.DeleteTag) // 删除标签
 				
-				// TODO: 这里可以添加标签的CRUD操作
-				// tags.POST("", tagHandler.CreateTag)                          // 创建标签
-				// tags.GET("", tagHandler.GetTagList)                          // 获取标签列表
-				// tags.GET("/:id", tagHandler.GetTag)                          // 获取标签详情
-				// tags.PUT("/:id", tagHandler.UpdateTag)                       // 更新标签
-				// tags.DELETE("/:id", tagHandler.DeleteTag)                    // 删除标签
+				// 标签相关的任务路由
+				tags.GET("/:id/tasks", taskHandler.GetTasksByTag)                // 根据标签获取任务
 			}
 		}
 	}
@@ -85,26 +177,31 @@ func SetupRoutes() *gin.Engine {
 	// 管理员路由组（需要管理员权限）
 	// 学习要点：权限控制，中间件链式调用
 	admin := api.Group("/admin")
-	// admin.Use(middleware.AdminAuthMiddleware()) // 管理员认证中间件（暂未实现）
+	admin.Use(authRequired, middleware.AdminAuthMiddleware()) // 先认证，再校验管理员角色
+	admin.Use(requestTimeout, rateLimit("admin", rateLimitCfg.GetAdmin()))
 	{
 		adminV1 := admin.Group("/v1")
 		{
 			// 管理员专用的用户管理接口
+			// 学习要点：权限中间件既可以挂在路由组上，也可以挂在单个路由上
+			canManageUsers := middleware.RequirePermission(models.PermissionUserManage)
+			canViewStats := middleware.RequirePermission(models.PermissionStatsView)
+			
 			adminUsers := adminV1.Group("/users")
 			{
-				adminUsers.GET("/stats", func(c *gin.Context) {
-					// TODO: 实现用户统计接口
-					c.JSON(200, gin.H{"message": "用户统计接口待实现"})
-				})
+				adminUsers.GET("/stats", canViewStats, adminHandler.GetUserStats)                // 用户统计
+				adminUsers.POST("/disable", canManageUsers, adminHandler.DisableUsers)           // 批量禁用用户
+				adminUsers.POST("/enable", canManageUsers, adminHandler.EnableUsers)             // 批量启用用户
+				adminUsers.POST("/:id/logout", canManageUsers, adminHandler.ForceLogout)         // 强制用户下线
+				adminUsers.PUT("/:id/role", canManageUsers, adminHandler.UpdateUserRole)         // 修改用户角色
+				adminUsers.DELETE("/:id", canManageUsers, adminHandler.DeleteUser)               // 删除用户（同时删除其任务）
 			}
 			
 			// 管理员专用的任务管理接口
 			adminTasks := adminV1.Group("/tasks")
+			adminTasks.Use(canViewStats)
 			{
-				adminTasks.GET("/stats", func(c *gin.Context) {
-					// TODO: 实现任务统计接口
-					c.JSON(200, gin.H{"message": "任务统计接口待实现"})
-				})
+				adminTasks.GET("/stats", adminHandler.GetTaskStats)             // 任务统计
 			}
 		}
 	}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"task-management-system/internal/middleware"
+	"task-management-system/internal/models"
+	"task-management-system/internal/services"
+)
+
+// TagHandler 标签处理器
+// 学习要点：标准的 RESTful CRUD 处理器
+type TagHandler struct {
+	tagService *services.TagService
+}
+
+// NewTagHandler 创建标签处理器实例
+func NewTagHandler(tagService *services.TagService) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// CreateTag 创建标签
+// @Summary 创建标签
+// @Description 创建新标签，名称不能重复，颜色格式为 #RRGGBB
+// @Tags 标签管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param tag body models.TagCreateRequest true "标签信息"
+// @Success 200 {object} models.Response{data=models.TagResponse} "创建成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 409 {object} models.Response "标签名称已存在"
+// @Failure 500 {object} models.Response "内部服务器错误"
+// @Router /api/v1/tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req models.TagCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	tag, err := h.tagService.CreateTag(c.Request.Context(), nil, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(tag))
+}
+
+// GetTagList 获取标签列表
+// @Summary 获取标签列表
+// @Description 分页获取全局标签列表，包含每个标签的使用次数（项目标签见 /projects/{id}/tags）
+// @Tags 标签管理
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} models.Response{data=models.PageResult} "获取成功"
+// @Failure 500 {object} models.Response "内部服务器错误"
+// @Router /api/v1/tags [get]
+func (h *TagHandler) GetTagList(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	result, err := h.tagService.GetTagList(c.Request.Context(), nil, page, pageSize)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(result))
+}
+
+// GetTag 获取标签详情
+// @Summary 获取标签详情
+// @Description 根据ID获取标签信息和使用次数，项目标签需要是项目成员
+// @Tags 标签管理
+// @Produce json
+// @Param id path int true "标签ID"
+// @Success 200 {object} models.Response{data=models.TagResponse} "获取成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 403 {object} models.Response "没有权限访问此项目"
+// @Failure 404 {object} models.Response "标签不存在"
+// @Failure 500 {object} models.Response "内部服务器错误"
+// @Router /api/v1/tags/{id} [get]
+func (h *TagHandler) GetTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("标签ID格式错误"))
+		return
+	}
+
+	userID, _ := middleware.GetCurrentUserID(c)
+	tag, err := h.tagService.GetTag(c.Request.Context(), uint(id), userID, middleware.GetCurrentRole(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(tag))
+}
+
+// UpdateTag 更新标签
+// @Summary 更新标签
+// @Description 修改标签名称或颜色
+// @Tags 标签管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "标签ID"
+// @Param tag body models.TagUpdateRequest true "更新的标签信息"
+// @Success 200 {object} models.Response{data=models.TagResponse} "更新成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 404 {object} models.Response "标签不存在"
+// @Failure 409 {object} models.Response "标签名称已存在"
+// @Failure 500 {object} models.Response "内部服务器错误"
+// @Router /api/v1/tags/{id} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("标签ID格式错误"))
+		return
+	}
+
+	var req models.TagUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	tag, err := h.tagService.UpdateTag(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(tag))
+}
+
+// DeleteTag 删除标签
+// @Summary 删除标签
+// @Description 先解除标签与所有任务的关联，再删除标签
+// @Tags 标签管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "标签ID"
+// @Success 200 {object} models.Response "删除成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 404 {object} models.Response "标签不存在"
+// @Failure 500 {object} models.Response "内部服务器错误"
+// @Router /api/v1/tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("标签ID格式错误"))
+		return
+	}
+
+	if err := h.tagService.DeleteTag(c.Request.Context(), uint(id)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse("标签删除成功"))
+}
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"task-management-system/internal/middleware"
 	"task-management-system/internal/models"
 	"task-management-system/internal/services"
 )
@@ -12,22 +14,25 @@ import (
 // TaskHandler 任务处理器结构体
 // 学习要点：复杂业务逻辑的HTTP处理，多条件查询，权限验证
 type TaskHandler struct {
-	taskService *services.TaskService
+	taskService    *services.TaskService
+	projectService *services.ProjectService
 }
 
 // NewTaskHandler 创建任务处理器实例
-func NewTaskHandler() *TaskHandler {
+func NewTaskHandler(taskService *services.TaskService, projectService *services.ProjectService) *TaskHandler {
 	return &TaskHandler{
-		taskService: services.NewTaskService(),
+		taskService:    taskService,
+		projectService: projectService,
 	}
 }
 
 // CreateTask 创建任务
 // @Summary 创建任务
-// @Description 为指定用户创建新任务
+// @Description 为当前登录用户创建新任务
 // @Tags 任务管理
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param task body models.TaskCreateRequest true "任务信息"
 // @Success 200 {object} models.Response{data=models.Task} "创建成功"
 // @Failure 400 {object} models.Response "请求参数错误"
@@ -37,28 +42,21 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 	// 绑定请求参数
 	var req models.TaskCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("请求参数错误: "+err.Error()))
+		c.Error(bindError(err))
 		return
 	}
 	
-	// 获取用户ID（实际项目中应该从JWT token中获取）
-	// 这里为了演示，从header中获取
-	userIDStr := c.GetHeader("X-User-ID")
-	if userIDStr == "" {
-		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("请先登录"))
-		return
-	}
-	
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("用户ID格式错误"))
+	// 获取当前登录用户ID（由认证中间件从JWT中解析）
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
 		return
 	}
 	
 	// 调用服务层创建任务
-	task, err := h.taskService.CreateTask(uint(userID), &req)
+	task, err := h.taskService.CreateTask(c.Request.Context(), userID, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error()))
+		c.Error(err)
 		return
 	}
 	
@@ -81,27 +79,33 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("任务ID格式错误"))
+		c.Error(invalidParam("任务ID格式错误"))
 		return
 	}
 	
 	// 调用服务层获取任务
-	task, err := h.taskService.GetTaskByID(uint(id))
+	task, err := h.taskService.GetTaskByID(c.Request.Context(), uint(id))
 	if err != nil {
-		if err.Error() == "任务不存在" {
-			c.JSON(http.StatusNotFound, models.NewErrorResponse(err.Error()))
-		} else {
-			c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error()))
-		}
+		c.Error(err)
 		return
 	}
 	
+	// 项目中的任务只对项目成员可见
+	if task.ProjectID != nil {
+		userID, _ := middleware.GetCurrentUserID(c)
+		err := h.projectService.Authorize(c.Request.Context(), *task.ProjectID, userID, middleware.GetCurrentRole(c), models.PermissionTaskRead)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+	}
+	
 	c.JSON(http.StatusOK, models.NewSuccessResponse(task))
 }
 
 // UpdateTask 更新任务
 // @Summary 更新任务
-// @Description 更新任务信息
+// @Description 更新任务信息；状态变更必须符合状态流转规则，前置任务没有全部结束时不能开始或完成任务
 // @Tags 任务管理
 // @Accept json
 // @Produce json
@@ -111,47 +115,37 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 // @Failure 400 {object} models.Response "请求参数错误"
 // @Failure 403 {object} models.Response "权限不足"
 // @Failure 404 {object} models.Response "任务不存在"
+// @Failure 409 {object} models.Response "不允许的状态变更或前置任务没有结束"
 // @Failure 500 {object} models.Response "内部服务器错误"
+// @Security BearerAuth
 // @Router /api/v1/tasks/{id} [put]
 func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	// 获取路径参数
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("任务ID格式错误"))
-		return
-	}
-	
-	// 获取用户ID
-	userIDStr := c.GetHeader("X-User-ID")
-	if userIDStr == "" {
-		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("请先登录"))
+		c.Error(invalidParam("任务ID格式错误"))
 		return
 	}
 	
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("用户ID格式错误"))
+	// 获取当前登录用户ID
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
 		return
 	}
 	
 	// 绑定请求参数
 	var req models.TaskUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("请求参数错误: "+err.Error()))
+		c.Error(bindError(err))
 		return
 	}
 	
 	// 调用服务层更新任务
-	task, err := h.taskService.UpdateTask(uint(id), uint(userID), &req)
+	task, err := h.taskService.UpdateTask(c.Request.Context(), uint(id), userID, &req)
 	if err != nil {
-		if err.Error() == "任务不存在" {
-			c.JSON(http.StatusNotFound, models.NewErrorResponse(err.Error()))
-		} else if err.Error() == "没有权限修改此任务" {
-			c.JSON(http.StatusForbidden, models.NewErrorResponse(err.Error()))
-		} else {
-			c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error()))
-		}
+		c.Error(err)
 		return
 	}
 	
@@ -169,38 +163,27 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 // @Failure 403 {object} models.Response "权限不足"
 // @Failure 404 {object} models.Response "任务不存在"
 // @Failure 500 {object} models.Response "内部服务器错误"
+// @Security BearerAuth
 // @Router /api/v1/tasks/{id} [delete]
 func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	// 获取路径参数
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("任务ID格式错误"))
+		c.Error(invalidParam("任务ID格式错误"))
 		return
 	}
 	
-	// 获取用户ID
-	userIDStr := c.GetHeader("X-User-ID")
-	if userIDStr == "" {
-		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("请先登录"))
-		return
-	}
-	
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("用户ID格式错误"))
+	// 获取当前登录用户ID
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
 		return
 	}
 	
 	// 调用服务层删除任务
-	if err := h.taskService.DeleteTask(uint(id), uint(userID)); err != nil {
-		if err.Error() == "任务不存在" {
-			c.JSON(http.StatusNotFound, models.NewErrorResponse(err.Error()))
-		} else if err.Error() == "没有权限删除此任务" {
-			c.JSON(http.StatusForbidden, models.NewErrorResponse(err.Error()))
-		} else {
-			c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error()))
-		}
+	if err := h.taskService.DeleteTask(c.Request.Context(), uint(id), userID); err != nil {
+		c.Error(err)
 		return
 	}
 	
@@ -215,7 +198,10 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 // @Param status query int false "任务状态 (0-待处理,1-进行中,2-已完成,3-已取消)"
 // @Param priority query int false "优先级 (1-低,2-中,3-高,4-紧急)"
 // @Param tag_id query int false "标签ID"
-// @Param user_id query int false "用户ID"
+// @Param user_id query int false "创建者ID"
+// @Param project_id query int false "项目ID（需要是项目成员）"
+// @Param assignee_id query int false "负责人ID"
+// @Param assigned_to_me query bool false "只看指派给当前用户的任务（需登录）"
 // @Param keyword query string false "搜索关键词"
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
@@ -230,10 +216,20 @@ func (h *TaskHandler) QueryTasks(c *gin.Context) {
 	// 绑定查询参数
 	// 学习要点：复杂查询参数的处理，类型转换，默认值设置
 	if c.ShouldBindQuery(&req) != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("查询参数格式错误"))
+		c.Error(invalidParam("查询参数格式错误"))
 		return
 	}
 	
+	// assigned_to_me 由当前登录用户决定负责人，优先于 assignee_id
+	if req.AssignedToMe {
+		userID, ok := middleware.GetCurrentUserID(c)
+		if !ok {
+			c.Error(errNotLoggedIn)
+			return
+		}
+		req.AssigneeID = &userID
+	}
+	
 	// 设置默认分页参数
 	if req.Page <= 0 {
 		req.Page = 1
@@ -242,10 +238,11 @@ func (h *TaskHandler) QueryTasks(c *gin.Context) {
 		req.PageSize = 10
 	}
 	
-	// 调用服务层查询任务
-	result, err := h.taskService.QueryTasks(&req)
+	// 调用服务层查询任务（只返回当前用户可见的项目任务）
+	setTaskViewer(c, &req)
+	result, err := h.taskService.QueryTasks(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error()))
+		c.Error(err)
 		return
 	}
 	
@@ -257,20 +254,20 @@ func (h *TaskHandler) QueryTasks(c *gin.Context) {
 // @Description 获取指定用户的所有任务
 // @Tags 任务管理
 // @Produce json
-// @Param user_id path int true "用户ID"
+// @Param id path int true "用户ID"
 // @Param status query int false "任务状态过滤"
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
 // @Success 200 {object} models.Response{data=models.PageResult} "获取成功"
 // @Failure 400 {object} models.Response "请求参数错误"
 // @Failure 500 {object} models.Response "内部服务器错误"
-// @Router /api/v1/users/{user_id}/tasks [get]
+// @Router /api/v1/users/{id}/tasks [get]
 func (h *TaskHandler) GetUserTasks(c *gin.Context) {
 	// 获取用户ID
-	userIDStr := c.Param("user_id")
+	userIDStr := c.Param("id")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("用户ID格式错误"))
+		c.Error(invalidParam("用户ID格式错误"))
 		return
 	}
 	
@@ -290,10 +287,11 @@ func (h *TaskHandler) GetUserTasks(c *gin.Context) {
 		req.PageSize = 10
 	}
 	
-	// 调用服务层查询任务
-	result, err := h.taskService.QueryTasks(&req)
+	// 调用服务层查询任务（只返回当前用户可见的项目任务）
+	setTaskViewer(c, &req)
+	result, err := h.taskService.QueryTasks(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error()))
+		c.Error(err)
 		return
 	}
 	
@@ -302,27 +300,28 @@ func (h *TaskHandler) GetUserTasks(c *gin.Context) {
 
 // GetUserTaskStats 获取用户任务统计
 // @Summary 获取用户任务统计
-// @Description 获取用户各状态任务的统计信息
+// @Description 获取用户各状态任务的统计信息，只统计不属于项目的任务和当前用户所在项目的任务（管理员统计全部）
 // @Tags 任务管理
 // @Produce json
-// @Param user_id path int true "用户ID"
+// @Param id path int true "用户ID"
 // @Success 200 {object} models.Response{data=map[string]int64} "获取成功"
 // @Failure 400 {object} models.Response "请求参数错误"
 // @Failure 500 {object} models.Response "内部服务器错误"
-// @Router /api/v1/users/{user_id}/tasks/stats [get]
+// @Router /api/v1/users/{id}/tasks/stats [get]
 func (h *TaskHandler) GetUserTaskStats(c *gin.Context) {
 	// 获取用户ID
-	userIDStr := c.Param("user_id")
+	userIDStr := c.Param("id")
 	userID, err := strconv.ParseUint(userIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("用户ID格式错误"))
+		c.Error(invalidParam("用户ID格式错误"))
 		return
 	}
 	
 	// 调用服务层获取统计信息
-	stats, err := h.taskService.GetUserTaskStats(uint(userID))
+	viewerID, _ := middleware.GetCurrentUserID(c)
+	stats, err := h.taskService.GetUserTaskStats(c.Request.Context(), uint(userID), viewerID, middleware.GetCurrentRole(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error()))
+		c.Error(err)
 		return
 	}
 	
@@ -334,19 +333,19 @@ func (h *TaskHandler) GetUserTaskStats(c *gin.Context) {
 // @Description 获取包含指定标签的所有任务
 // @Tags 任务管理
 // @Produce json
-// @Param tag_id path int true "标签ID"
+// @Param id path int true "标签ID"
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
 // @Success 200 {object} models.Response{data=models.PageResult} "获取成功"
 // @Failure 400 {object} models.Response "请求参数错误"
 // @Failure 500 {object} models.Response "内部服务器错误"
-// @Router /api/v1/tags/{tag_id}/tasks [get]
+// @Router /api/v1/tags/{id}/tasks [get]
 func (h *TaskHandler) GetTasksByTag(c *gin.Context) {
 	// 获取标签ID
-	tagIDStr := c.Param("tag_id")
+	tagIDStr := c.Param("id")
 	tagID, err := strconv.ParseUint(tagIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("标签ID格式错误"))
+		c.Error(invalidParam("标签ID格式错误"))
 		return
 	}
 	
@@ -366,10 +365,11 @@ func (h *TaskHandler) GetTasksByTag(c *gin.Context) {
 		req.PageSize = 10
 	}
 	
-	// 调用服务层查询任务
-	result, err := h.taskService.QueryTasks(&req)
+	// 调用服务层查询任务（只返回当前用户可见的项目任务）
+	setTaskViewer(c, &req)
+	result, err := h.taskService.QueryTasks(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error()))
+		c.Error(err)
 		return
 	}
 	
@@ -378,7 +378,7 @@ func (h *TaskHandler) GetTasksByTag(c *gin.Context) {
 
 // MarkTaskComplete 标记任务为完成
 // @Summary 标记任务为完成
-// @Description 将任务状态设置为已完成
+// @Description 将进行中的任务设置为已完成，其他状态的任务或前置任务没有全部结束时拒绝
 // @Tags 任务管理
 // @Produce json
 // @Param id path int true "任务ID"
@@ -386,48 +386,298 @@ func (h *TaskHandler) GetTasksByTag(c *gin.Context) {
 // @Failure 400 {object} models.Response "请求参数错误"
 // @Failure 403 {object} models.Response "权限不足"
 // @Failure 404 {object} models.Response "任务不存在"
+// @Failure 409 {object} models.Response "任务不是进行中或前置任务没有结束"
 // @Failure 500 {object} models.Response "内部服务器错误"
+// @Security BearerAuth
 // @Router /api/v1/tasks/{id}/complete [post]
 func (h *TaskHandler) MarkTaskComplete(c *gin.Context) {
 	// 获取任务ID
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("任务ID格式错误"))
+		c.Error(invalidParam("任务ID格式错误"))
 		return
 	}
 	
-	// 获取用户ID
-	userIDStr := c.GetHeader("X-User-ID")
-	if userIDStr == "" {
-		c.JSON(http.StatusUnauthorized, models.NewErrorResponse("请先登录"))
+	// 获取当前登录用户ID
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
 		return
 	}
 	
-	userID, err := strconv.ParseUint(userIDStr, 10, 32)
+	// 调用服务层标记完成（任务动态中记录为 completed）
+	task, err := h.taskService.CompleteTask(c.Request.Context(), uint(id), userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("用户ID格式错误"))
+		c.Error(err)
 		return
 	}
 	
-	// 构建更新请求
-	status := models.TaskStatusCompleted
-	req := models.TaskUpdateRequest{
-		Status: &status,
+	c.JSON(http.StatusOK, models.NewSuccessResponse(task))
+}
+
+// ListTaskActivities 获取任务动态
+// @Summary 获取任务动态
+// @Description 游标分页获取任务的修改记录（字段旧值和新值），最新的在前；项目任务需要是项目成员
+// @Tags 任务管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Param cursor query string false "上一页返回的 next_cursor"
+// @Param limit query int false "每页数量" default(20)
+// @Success 200 {object} models.Response{data=models.CursorPage} "获取成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 401 {object} models.Response "未登录"
+// @Failure 403 {object} models.Response "没有权限访问此项目"
+// @Failure 404 {object} models.Response "任务不存在"
+// @Router /api/v1/tasks/{id}/activity [get]
+func (h *TaskHandler) ListTaskActivities(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("任务ID格式错误"))
+		return
 	}
-	
-	// 调用服务层更新任务
-	task, err := h.taskService.UpdateTask(uint(id), uint(userID), &req)
+
+	var query models.CursorQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(invalidParam("查询参数格式错误"))
+		return
+	}
+
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
+		return
+	}
+
+	page, err := h.taskService.ListTaskActivities(c.Request.Context(), uint(id), userID, middleware.GetCurrentRole(c), query)
 	if err != nil {
-		if err.Error() == "任务不存在" {
-			c.JSON(http.StatusNotFound, models.NewErrorResponse(err.Error()))
-		} else if err.Error() == "没有权限修改此任务" {
-			c.JSON(http.StatusForbidden, models.NewErrorResponse(err.Error()))
-		} else {
-			c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error()))
-		}
+		c.Error(err)
 		return
 	}
-	
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(page))
+}
+
+// AssignTask 指派任务负责人
+// @Summary 指派任务负责人
+// @Description 为任务添加一个或多个负责人，负责人可以修改任务（创建者和现有负责人可以指派）
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Param assignees body models.TaskAssignRequest true "负责人用户ID列表"
+// @Success 200 {object} models.Response{data=models.Task} "指派成功"
+// @Failure 400 {object} models.Response "请求参数错误或用户不存在"
+// @Failure 403 {object} models.Response "权限不足"
+// @Failure 404 {object} models.Response "任务不存在"
+// @Router /api/v1/tasks/{id}/assignees [post]
+func (h *TaskHandler) AssignTask(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("任务ID格式错误"))
+		return
+	}
+
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
+		return
+	}
+
+	var req models.TaskAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	task, err := h.taskService.AssignTask(c.Request.Context(), uint(id), userID, req.UserIDs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(task))
+}
+
+// UnassignTask 移除任务负责人
+// @Summary 移除任务负责人
+// @Description 移除任务的一个负责人，负责人也可以移除自己
+// @Tags 任务管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Param user_id path int true "负责人用户ID"
+// @Success 200 {object} models.Response{data=models.Task} "移除成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 403 {object} models.Response "权限不足"
+// @Failure 404 {object} models.Response "任务不存在"
+// @Router /api/v1/tasks/{id}/assignees/{user_id} [delete]
+func (h *TaskHandler) UnassignTask(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("任务ID格式错误"))
+		return
+	}
+	assigneeID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("用户ID格式错误"))
+		return
+	}
+
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
+		return
+	}
+
+	task, err := h.taskService.UnassignTask(c.Request.Context(), uint(id), userID, uint(assigneeID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
 	c.JSON(http.StatusOK, models.NewSuccessResponse(task))
-}
\ No newline at end of file
+}
+
+// AddDependency 添加前置任务
+// @Summary 添加前置任务
+// @Description 前置任务结束（完成或取消）前，任务不能开始或完成；前置任务必须属于同一项目，不能形成循环依赖
+// @Tags 任务管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Param dependency body models.TaskDependencyRequest true "前置任务ID"
+// @Success 200 {object} models.Response{data=models.Task} "添加成功"
+// @Failure 400 {object} models.Response "请求参数错误、前置任务无效或形成循环依赖"
+// @Failure 403 {object} models.Response "权限不足"
+// @Failure 404 {object} models.Response "任务不存在"
+// @Router /api/v1/tasks/{id}/dependencies [post]
+func (h *TaskHandler) AddDependency(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("任务ID格式错误"))
+		return
+	}
+
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
+		return
+	}
+
+	var req models.TaskDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
+		return
+	}
+
+	task, err := h.taskService.AddDependency(c.Request.Context(), uint(id), userID, req.BlockerID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(task))
+}
+
+// RemoveDependency 移除前置任务
+// @Summary 移除前置任务
+// @Tags 任务管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Param blocker_id path int true "前置任务ID"
+// @Success 200 {object} models.Response{data=models.Task} "移除成功"
+// @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 403 {object} models.Response "权限不足"
+// @Failure 404 {object} models.Response "任务不存在"
+// @Router /api/v1/tasks/{id}/dependencies/{blocker_id} [delete]
+func (h *TaskHandler) RemoveDependency(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("任务ID格式错误"))
+		return
+	}
+	blockerID, err := strconv.ParseUint(c.Param("blocker_id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("前置任务ID格式错误"))
+		return
+	}
+
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
+		return
+	}
+
+	task, err := h.taskService.RemoveDependency(c.Request.Context(), uint(id), userID, uint(blockerID))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(task))
+}
+
+// WatchTask 关注任务
+// @Summary 关注任务
+// @Description 当前用户关注任务，关注者只能查看任务；项目任务需要是项目成员
+// @Tags 任务管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Success 200 {object} models.Response "关注成功"
+// @Failure 403 {object} models.Response "没有权限访问此项目"
+// @Failure 404 {object} models.Response "任务不存在"
+// @Router /api/v1/tasks/{id}/watch [post]
+func (h *TaskHandler) WatchTask(c *gin.Context) {
+	role := middleware.GetCurrentRole(c)
+	h.changeWatch(c, func(ctx context.Context, id, userID uint) error {
+		return h.taskService.WatchTask(ctx, id, userID, role)
+	}, "关注成功")
+}
+
+// UnwatchTask 取消关注任务
+// @Summary 取消关注任务
+// @Description 当前用户取消关注任务
+// @Tags 任务管理
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "任务ID"
+// @Success 200 {object} models.Response "取消关注成功"
+// @Failure 404 {object} models.Response "任务不存在"
+// @Router /api/v1/tasks/{id}/watch [delete]
+func (h *TaskHandler) UnwatchTask(c *gin.Context) {
+	h.changeWatch(c, h.taskService.UnwatchTask, "取消关注成功")
+}
+
+// changeWatch 关注和取消关注的公共处理
+func (h *TaskHandler) changeWatch(c *gin.Context, change func(ctx context.Context, id, userID uint) error, message string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.Error(invalidParam("任务ID格式错误"))
+		return
+	}
+
+	userID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
+		return
+	}
+
+	if err := change(c.Request.Context(), uint(id), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(message))
+}
+
+// setTaskViewer 根据当前登录用户设置任务可见范围：管理员可以查看所有项目的任务
+func setTaskViewer(c *gin.Context, req *models.TaskQueryRequest) {
+	req.ViewerID, _ = middleware.GetCurrentUserID(c)
+	req.ViewAllProjects = middleware.GetCurrentRole(c) == models.RoleAdmin
+}
@@ -7,6 +7,8 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/middleware"
 	"task-management-system/internal/models"
 	"task-management-system/internal/services"
 )
@@ -18,9 +20,9 @@ type UserHandler struct {
 }
 
 // NewUserHandler 创建用户处理器实例
-func NewUserHandler() *UserHandler {
+func NewUserHandler(userService *services.UserService) *UserHandler {
 	return &UserHandler{
-		userService: services.NewUserService(),
+		userService: userService,
 	}
 }
 
@@ -41,14 +43,14 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 	// 绑定并验证请求参数
 	// 学习要点：Gin的参数绑定和验证功能
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("请求参数错误: "+err.Error()))
+		c.Error(bindError(err))
 		return
 	}
 	
 	// 调用服务层创建用户
-	user, err := h.userService.CreateUser(&req)
+	user, err := h.userService.CreateUser(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error()))
+		c.Error(err)
 		return
 	}
 	
@@ -72,20 +74,16 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("用户ID格式错误"))
+		c.Error(invalidParam("用户ID格式错误"))
 		return
 	}
 	
 	// 调用服务层获取用户
-	user, err := h.userService.GetUserByID(uint(id))
+	user, err := h.userService.GetUserByID(c.Request.Context(), uint(id))
 	if err != nil {
-		// 根据错误类型返回不同的状态码
+		// 错误交给统一错误中间件，按错误类别返回不同的状态码
 		// 学习要点：错误处理的最佳实践
-		if err.Error() == "用户不存在" {
-			c.JSON(http.StatusNotFound, models.NewErrorResponse(err.Error()))
-		} else {
-			c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error()))
-		}
+		c.Error(err)
 		return
 	}
 	
@@ -94,14 +92,16 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 
 // UpdateUser 更新用户信息
 // @Summary 更新用户信息
-// @Description 更新用户的基本信息
+// @Description 更新用户的基本信息，只能修改自己的信息（有用户管理权限的可以修改任意用户）
 // @Tags 用户管理
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "用户ID"
 // @Param user body models.UserUpdateRequest true "更新的用户信息"
 // @Success 200 {object} models.Response{data=models.UserResponse} "更新成功"
 // @Failure 400 {object} models.Response "请求参数错误"
+// @Failure 403 {object} models.Response "权限不足"
 // @Failure 404 {object} models.Response "用户不存在"
 // @Failure 500 {object} models.Response "内部服务器错误"
 // @Router /api/v1/users/{id} [put]
@@ -110,62 +110,87 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("用户ID格式错误"))
+		c.Error(invalidParam("用户ID格式错误"))
+		return
+	}
+	
+	// 只允许修改自己的信息，管理员除外
+	currentUserID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
+		return
+	}
+	if currentUserID != uint(id) && !models.RoleHasPermission(middleware.GetCurrentRole(c), models.PermissionUserManage) {
+		c.Error(apperr.Forbidden("user_update_forbidden", "只能修改自己的信息"))
 		return
 	}
 	
 	// 绑定请求参数
 	var req models.UserUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("请求参数错误: "+err.Error()))
+		c.Error(bindError(err))
 		return
 	}
 	
 	// 调用服务层更新用户
-	user, err := h.userService.UpdateUser(uint(id), &req)
+	user, err := h.userService.UpdateUser(c.Request.Context(), uint(id), &req)
 	if err != nil {
-		if err.Error() == "用户不存在" {
-			c.JSON(http.StatusNotFound, models.NewErrorResponse(err.Error()))
-		} else {
-			c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error()))
-		}
+		c.Error(err)
 		return
 	}
 	
 	c.JSON(http.StatusOK, models.NewSuccessResponse(user.ToResponse()))
 }
 
-// DeleteUser 删除用户
-// @Summary 删除用户
-// @Description 软删除用户账户
+// ChangePassword 修改密码
+// @Summary 修改密码
+// @Description 验证原密码后设置新密码，只能修改自己的密码；修改后之前签发的令牌全部失效，需要重新登录
 // @Tags 用户管理
+// @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param id path int true "用户ID"
-// @Success 200 {object} models.Response "删除成功"
+// @Param body body models.ChangePasswordRequest true "原密码和新密码"
+// @Success 200 {object} models.Response "修改成功"
 // @Failure 400 {object} models.Response "请求参数错误"
-// @Failure 404 {object} models.Response "用户不存在"
+// @Failure 401 {object} models.Response "原密码错误"
+// @Failure 403 {object} models.Response "权限不足"
 // @Failure 500 {object} models.Response "内部服务器错误"
-// @Router /api/v1/users/{id} [delete]
-func (h *UserHandler) DeleteUser(c *gin.Context) {
+// @Router /api/v1/users/{id}/password [put]
+func (h *UserHandler) ChangePassword(c *gin.Context) {
 	// 获取路径参数
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("用户ID格式错误"))
+		c.Error(invalidParam("用户ID格式错误"))
+		return
+	}
+	
+	// 只允许修改自己的密码
+	currentUserID, ok := middleware.GetCurrentUserID(c)
+	if !ok {
+		c.Error(errNotLoggedIn)
+		return
+	}
+	if currentUserID != uint(id) {
+		c.Error(apperr.Forbidden("password_change_forbidden", "只能修改自己的密码"))
+		return
+	}
+	
+	// 绑定请求参数
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindError(err))
 		return
 	}
 	
-	// 调用服务层删除用户
-	if err := h.userService.DeleteUser(uint(id)); err != nil {
-		if err.Error() == "用户不存在" {
-			c.JSON(http.StatusNotFound, models.NewErrorResponse(err.Error()))
-		} else {
-			c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error()))
-		}
+	// 调用服务层修改密码
+	if err := h.userService.ChangePassword(c.Request.Context(), uint(id), &req); err != nil {
+		c.Error(err)
 		return
 	}
 	
-	c.JSON(http.StatusOK, models.NewSuccessResponse("用户删除成功"))
+	c.JSON(http.StatusOK, models.NewSuccessResponse("密码修改成功"))
 }
 
 // GetUserList 获取用户列表
@@ -194,9 +219,9 @@ func (h *UserHandler) GetUserList(c *gin.Context) {
 	}
 	
 	// 调用服务层获取用户列表
-	result, err := h.userService.GetUserList(page, pageSize)
+	result, err := h.userService.GetUserList(c.Request.Context(), page, pageSize)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error()))
+		c.Error(err)
 		return
 	}
 	
@@ -216,48 +241,16 @@ func (h *UserHandler) GetUserList(c *gin.Context) {
 func (h *UserHandler) GetUserByUsername(c *gin.Context) {
 	username := c.Param("username")
 	if username == "" {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("用户名不能为空"))
+		c.Error(invalidParam("用户名不能为空"))
 		return
 	}
 	
 	// 调用服务层获取用户
-	user, err := h.userService.GetUserByUsername(username)
+	user, err := h.userService.GetUserByUsername(c.Request.Context(), username)
 	if err != nil {
-		if err.Error() == "用户不存在" {
-			c.JSON(http.StatusNotFound, models.NewErrorResponse(err.Error()))
-		} else {
-			c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error()))
-		}
+		c.Error(err)
 		return
 	}
 	
 	c.JSON(http.StatusOK, models.NewSuccessResponse(user.ToResponse()))
 }
-
-// UpdateLastLoginTime 更新最后登录时间
-// @Summary 更新最后登录时间
-// @Description 记录用户的最后登录时间
-// @Tags 用户管理
-// @Produce json
-// @Param id path int true "用户ID"
-// @Success 200 {object} models.Response "更新成功"
-// @Failure 400 {object} models.Response "请求参数错误"
-// @Failure 500 {object} models.Response "内部服务器错误"
-// @Router /api/v1/users/{id}/login [post]
-func (h *UserHandler) UpdateLastLoginTime(c *gin.Context) {
-	// 获取路径参数
-	idStr := c.Param("id")
-	id, err := strconv.ParseUint(idStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, models.NewErrorResponse("用户ID格式错误"))
-		return
-	}
-	
-	// 调用服务层更新登录时间
-	if err := h.userService.UpdateLastLoginTime(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, models.NewErrorResponse(err.Error()))
-		return
-	}
-	
-	c.JSON(http.StatusOK, models.NewSuccessResponse("登录时间更新成功"))
-}
\ No newline at end of file
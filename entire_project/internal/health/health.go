@@ -0,0 +1,118 @@
+// Package health 健康检查
+// 学习要点：存活探针（liveness）只说明进程还在响应，失败时 k8s 会重启容器；
+// 就绪探针（readiness）检查依赖是否可用，失败时只是暂停向该实例转发流量
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCheckTimeout 单个依赖检查的默认超时
+const DefaultCheckTimeout = 2 * time.Second
+
+// 检查状态
+const (
+	StatusUp       = "up"        // 依赖可用
+	StatusDown     = "down"      // 依赖不可用
+	StatusReady    = "ready"     // 实例就绪
+	StatusNotReady = "not_ready" // 实例未就绪
+	StatusDraining = "draining"  // 正在关闭，不再接收新流量
+)
+
+// Check 依赖检查项
+type Check struct {
+	Name string                          // 依赖名称，如 mysql、redis
+	Ping func(ctx context.Context) error // 检查函数
+}
+
+// CheckResult 单个依赖的检查结果
+type CheckResult struct {
+	Status    string `json:"status"`          // up 或 down
+	LatencyMS int64  `json:"latency_ms"`      // 检查耗时(毫秒)
+	Error     string `json:"error,omitempty"` // 失败原因
+}
+
+// Report 就绪检查报告
+type Report struct {
+	Status string                 `json:"status"` // ready、not_ready 或 draining
+	Checks map[string]CheckResult `json:"checks"` // 各依赖的检查结果
+}
+
+// Ready 是否就绪
+func (r *Report) Ready() bool {
+	return r.Status == StatusReady
+}
+
+// Checker 就绪检查器
+// 学习要点：各依赖并发检查并各自设置超时，总耗时取决于最慢的依赖而不是所有依赖之和
+type Checker struct {
+	checks   []Check
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// NewChecker 创建就绪检查器
+func NewChecker(timeout time.Duration, checks ...Check) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+	return &Checker{checks: checks, timeout: timeout}
+}
+
+// SetDraining 标记实例正在关闭
+// 学习要点：优雅关闭时先让就绪探针失败，负载均衡摘除实例后再停止服务，避免新请求打到正在关闭的实例
+func (c *Checker) SetDraining() {
+	c.draining.Store(true)
+}
+
+// Draining 是否正在关闭
+func (c *Checker) Draining() bool {
+	return c.draining.Load()
+}
+
+// Check 检查所有依赖
+func (c *Checker) Check(ctx context.Context) *Report {
+	report := &Report{Status: StatusReady, Checks: make(map[string]CheckResult, len(c.checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range c.checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+			result := c.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusNotReady
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	// 关闭期间即使依赖正常也返回未就绪
+	if c.Draining() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+// run 带超时执行单个检查
+func (c *Checker) run(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Ping(ctx)
+	result := CheckResult{Status: StatusUp, LatencyMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecker_Check(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	tests := []struct {
+		name       string
+		checks     []Check
+		draining   bool
+		wantStatus string
+		wantChecks map[string]string
+	}{
+		{"全部可用", []Check{{"mysql", up}, {"redis", up}}, false, StatusReady,
+			map[string]string{"mysql": StatusUp, "redis": StatusUp}},
+		{"一个依赖不可用", []Check{{"mysql", up}, {"redis", down}}, false, StatusNotReady,
+			map[string]string{"mysql": StatusUp, "redis": StatusDown}},
+		{"依赖检查超时", []Check{{"mysql", slow}}, false, StatusNotReady,
+			map[string]string{"mysql": StatusDown}},
+		{"关闭期间", []Check{{"mysql", up}}, true, StatusDraining,
+			map[string]string{"mysql": StatusUp}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(50*time.Millisecond, tt.checks...)
+			if tt.draining {
+				checker.SetDraining()
+			}
+
+			report := checker.Check(context.Background())
+			assert.Equal(t, tt.wantStatus, report.Status)
+			assert.Equal(t, tt.wantStatus == StatusReady, report.Ready())
+			for name, status := range tt.wantChecks {
+				assert.Equal(t, status, report.Checks[name].Status, name)
+				if status == StatusDown {
+					assert.NotEmpty(t, report.Checks[name].Error)
+				}
+			}
+		})
+	}
+}
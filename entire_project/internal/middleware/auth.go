@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/auth"
+)
+
+// 上下文键常量
+// 学习要点：认证中间件把当前用户写入 gin.Context，处理器从这里读取而不是解析请求头
+const (
+	ContextUserIDKey   = "user_id"  // 当前用户ID
+	ContextUsernameKey = "username" // 当前用户名
+	ContextRoleKey     = "role"     // 当前用户角色
+
+	ContextRequestIDKey = "request_id" // 请求ID
+)
+
+// 认证与授权失败的错误
+var (
+	errNotLoggedIn      = apperr.Unauthorized("unauthenticated", "请先登录")
+	errPermissionDenied = apperr.Forbidden("permission_denied", "权限不足")
+)
+
+// errTokenRevoked 令牌签发于用户被强制下线（禁用、修改角色、删除）之前
+var errTokenRevoked = apperr.Unauthorized("token_revoked", "令牌已失效，请重新登录")
+
+// TokenRevocationChecker 判断令牌是否已被吊销
+// 学习要点：中间件只依赖需要的方法，由服务层（AuthService）实现，避免中间件依赖服务层
+type TokenRevocationChecker interface {
+	IsTokenRevoked(ctx context.Context, claims *auth.Claims) (bool, error)
+}
+
+// AuthMiddleware JWT认证中间件
+// 学习要点：Bearer Token 的解析，认证失败时中断请求链；
+// 签名和有效期之外还要检查用户是否被强制下线，禁用和修改角色后旧的访问令牌立即失效
+func AuthMiddleware(tokenManager *auth.TokenManager, revocation TokenRevocationChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// 从 Authorization 头中提取令牌：Bearer <token>
+		header := c.GetHeader("Authorization")
+		tokenString, found := strings.CutPrefix(header, "Bearer ")
+		if !found || tokenString == "" {
+			abortWithError(c, errNotLoggedIn)
+			return
+		}
+
+		// 校验令牌
+		claims, err := tokenManager.ParseAccessToken(tokenString)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		revoked, err := revocation.IsTokenRevoked(c.Request.Context(), claims)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		if revoked {
+			abortWithError(c, errTokenRevoked)
+			return
+		}
+
+		// 将当前用户写入上下文
+		c.Set(ContextUserIDKey, claims.UserID)
+		c.Set(ContextUsernameKey, claims.Username)
+		c.Set(ContextRoleKey, claims.Role)
+
+		// 继续处理请求
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware 可选认证中间件
+// 学习要点：携带有效令牌时写入当前用户，否则按匿名请求继续处理，不中断请求链；
+// 供限流等需要区分用户但不要求登录的中间件使用；已吊销的令牌同样按匿名请求处理
+func OptionalAuthMiddleware(tokenManager *auth.TokenManager, revocation TokenRevocationChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if tokenString, found := strings.CutPrefix(header, "Bearer "); found && tokenString != "" {
+			if claims, err := tokenManager.ParseAccessToken(tokenString); err == nil {
+				setCurrentUserUnlessRevoked(c, revocation, claims)
+			}
+		}
+		c.Next()
+	}
+}
+
+// setCurrentUserUnlessRevoked 令牌没有被吊销时写入当前用户
+func setCurrentUserUnlessRevoked(c *gin.Context, revocation TokenRevocationChecker, claims *auth.Claims) {
+	ctx := c.Request.Context()
+	revoked, err := revocation.IsTokenRevoked(ctx, claims)
+	if err != nil {
+		slog.WarnContext(ctx, "校验令牌吊销状态失败，按匿名请求处理", "error", err)
+		return
+	}
+	if revoked {
+		return
+	}
+	c.Set(ContextUserIDKey, claims.UserID)
+	c.Set(ContextUsernameKey, claims.Username)
+	c.Set(ContextRoleKey, claims.Role)
+}
+
+// GetCurrentUserID 获取当前登录用户ID
+// 学习要点：类型断言，只有经过 AuthMiddleware 的路由才能取到值
+func GetCurrentUserID(c *gin.Context) (uint, bool) {
+	value, exists := c.Get(ContextUserIDKey)
+	if !exists {
+		return 0, false
+	}
+	userID, ok := value.(uint)
+	return userID, ok
+}
+
+// GetCurrentRole 获取当前登录用户角色
+func GetCurrentRole(c *gin.Context) string {
+	return c.GetString(ContextRoleKey)
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-management-system/internal/auth"
+	"task-management-system/internal/config"
+	"task-management-system/internal/models"
+)
+
+// revokedUsers 测试用的吊销检查：列表中用户的令牌都视为已吊销
+type revokedUsers map[uint]bool
+
+func (r revokedUsers) IsTokenRevoked(_ context.Context, claims *auth.Claims) (bool, error) {
+	return r[claims.UserID], nil
+}
+
+func TestAuthMiddleware_RevokedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tokenManager := auth.NewTokenManager(config.AuthConfig{Secret: "secret", Issuer: "test"})
+	r := gin.New()
+	r.Use(ErrorMiddleware(), AuthMiddleware(tokenManager, revokedUsers{2: true}))
+	r.GET("/me", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		userID   uint
+		wantCode int
+	}{
+		{"有效令牌", 1, http.StatusOK},
+		{"用户被强制下线后旧令牌失效", 2, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair, err := tokenManager.GenerateTokenPair(tt.userID, "alice", models.RoleMember)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", "Bearer "+pair.AccessToken)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode == http.StatusUnauthorized {
+				var resp models.Response
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, "token_revoked", resp.ErrorCode)
+			}
+		})
+	}
+}
@@ -4,31 +4,198 @@ package middleware
 
 import (
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"task-management-system/internal/config"
 )
 
 // CorsMiddleware CORS跨域中间件
-// 学习要点：跨域资源共享(CORS)的处理，安全头设置
-func CorsMiddleware() gin.HandlerFunc {
-	return gin.HandlerFunc(func(c *gin.Context) {
+// 学习要点：跨域资源共享(CORS)的处理。请求的 Origin 在白名单中时原样返回，
+// 不在白名单中时不返回任何 CORS 头，由浏览器拦截；非浏览器客户端不受影响
+func CorsMiddleware(cfg *config.CORSConfig) gin.HandlerFunc {
+	policies := newCORSPolicies(cfg)
+
+	return func(c *gin.Context) {
+		// 响应内容随 Origin 变化，告诉缓存和CDN按 Origin 区分
+		c.Writer.Header().Add("Vary", "Origin")
+
+		// 同源请求和非浏览器请求没有 Origin 头，不需要处理
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		policy := policies.match(c.Request.URL.Path)
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+
+		allowOrigin, ok := policy.allowOrigin(origin)
+		if !ok {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
 		// 设置CORS响应头
 		// 学习要点：各种CORS头的含义和作用
-		c.Header("Access-Control-Allow-Origin", "*")                                         // 允许所有域名访问
-		c.Header("Access-Control-Allow-Methods", "GET,POST,PUT,PATCH,DELETE,HEAD,OPTIONS")  // 允许的HTTP方法
-		c.Header("Access-Control-Allow-Headers", "Origin,Content-Length,Content-Type,X-User-ID,Authorization,X-Request-ID") // 允许的请求头
-		c.Header("Access-Control-Expose-Headers", "Content-Length,Access-Control-Allow-Origin,Access-Control-Allow-Headers") // 暴露的响应头
-		c.Header("Access-Control-Allow-Credentials", "true")                                 // 允许携带认证信息
-		c.Header("Access-Control-Max-Age", "86400")                                          // 预检请求缓存时间(秒)
-		
+		c.Header("Access-Control-Allow-Origin", allowOrigin) // 允许的来源（匹配时原样返回）
+		if policy.allowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true") // 允许携带认证信息
+		}
+
 		// 处理OPTIONS预检请求
-		// 学习要点：CORS预检请求的处理
-		if c.Request.Method == "OPTIONS" {
+		// 学习要点：预检请求只返回允许的方法和请求头，不进入后续的处理器
+		if preflight {
+			c.Header("Access-Control-Allow-Methods", policy.allowMethods) // 允许的HTTP方法
+			c.Header("Access-Control-Allow-Headers", policy.allowHeaders) // 允许的请求头
+			c.Header("Access-Control-Max-Age", policy.maxAge)             // 预检请求缓存时间(秒)
 			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
-		
+
+		c.Header("Access-Control-Expose-Headers", policy.exposeHeaders) // 暴露的响应头
+
 		// 继续处理请求
 		c.Next()
+	}
+}
+
+// corsPolicy 编译后的跨域规则
+type corsPolicy struct {
+	pathPrefix       string
+	origins          []originPattern
+	allowAny         bool
+	allowCredentials bool
+	allowMethods     string
+	allowHeaders     string
+	exposeHeaders    string
+	maxAge           string
+}
+
+// corsPolicies 全局规则和按路径前缀覆盖的规则
+type corsPolicies struct {
+	defaults *corsPolicy
+	routes   []*corsPolicy // 按路径前缀长度降序，优先匹配最具体的规则
+}
+
+// newCORSPolicies 根据配置编译跨域规则
+// 学习要点：启动时把配置解析成匹配结构，每个请求只做比较，不再重复解析字符串
+func newCORSPolicies(cfg *config.CORSConfig) *corsPolicies {
+	defaults := &corsPolicy{
+		allowCredentials: cfg.AllowCredentials,
+		allowMethods:     strings.Join(cfg.GetAllowedMethods(), ","),
+		allowHeaders:     strings.Join(cfg.GetAllowedHeaders(), ","),
+		exposeHeaders:    strings.Join(cfg.GetExposedHeaders(), ","),
+		maxAge:           strconv.Itoa(int(cfg.GetMaxAge().Seconds())),
+	}
+	defaults.setOrigins(cfg.AllowedOrigins)
+
+	policies := &corsPolicies{defaults: defaults}
+	for _, route := range cfg.Routes {
+		policy := *defaults
+		policy.pathPrefix = route.PathPrefix
+		policy.allowCredentials = route.AllowCredentials
+		policy.setOrigins(route.AllowedOrigins)
+		if len(route.AllowedMethods) > 0 {
+			policy.allowMethods = strings.Join(route.AllowedMethods, ",")
+		}
+		if len(route.AllowedHeaders) > 0 {
+			policy.allowHeaders = strings.Join(route.AllowedHeaders, ",")
+		}
+		policies.routes = append(policies.routes, &policy)
+	}
+	sort.SliceStable(policies.routes, func(i, j int) bool {
+		return len(policies.routes[i].pathPrefix) > len(policies.routes[j].pathPrefix)
 	})
-}
\ No newline at end of file
+	return policies
+}
+
+// match 获取请求路径对应的规则
+func (p *corsPolicies) match(path string) *corsPolicy {
+	for _, policy := range p.routes {
+		if strings.HasPrefix(path, policy.pathPrefix) {
+			return policy
+		}
+	}
+	return p.defaults
+}
+
+// setOrigins 解析来源白名单，无法解析的来源被忽略（配置校验时已经拒绝）
+func (p *corsPolicy) setOrigins(origins []string) {
+	p.origins = nil
+	p.allowAny = false
+	for _, origin := range origins {
+		if origin == "*" {
+			p.allowAny = true
+			continue
+		}
+		if pattern, ok := parseOriginPattern(origin); ok {
+			p.origins = append(p.origins, pattern)
+		}
+	}
+}
+
+// allowOrigin 判断来源是否允许，返回 Access-Control-Allow-Origin 的值
+func (p *corsPolicy) allowOrigin(origin string) (string, bool) {
+	if p.allowAny && !p.allowCredentials {
+		return "*", true
+	}
+	for _, pattern := range p.origins {
+		if pattern.match(origin) {
+			return origin, true
+		}
+	}
+	return "", false
+}
+
+// originPattern 来源匹配规则
+type originPattern struct {
+	scheme   string
+	host     string // 精确匹配的主机名，或通配时的父域名
+	port     string
+	wildcard bool // 是否匹配任意子域名
+}
+
+// parseOriginPattern 解析 scheme://host[:port] 或 scheme://*.domain[:port]
+func parseOriginPattern(origin string) (originPattern, bool) {
+	pattern := originPattern{}
+	rest, wildcard := strings.CutPrefix(origin, "https://*.")
+	if wildcard {
+		origin = "https://" + rest
+	} else if rest, wildcard = strings.CutPrefix(origin, "http://*."); wildcard {
+		origin = "http://" + rest
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return pattern, false
+	}
+	pattern.scheme = strings.ToLower(u.Scheme)
+	pattern.host = strings.ToLower(u.Hostname())
+	pattern.port = u.Port()
+	pattern.wildcard = wildcard
+	return pattern, true
+}
+
+// match 判断请求的 Origin 是否匹配
+// 学习要点：协议和端口必须一致；*.example.com 匹配任意层级的子域名，但不匹配 example.com 本身，
+// 也不会匹配 evil-example.com 这类只是后缀相同的域名
+func (p originPattern) match(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || strings.ToLower(u.Scheme) != p.scheme || u.Port() != p.port {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	if !p.wildcard {
+		return host == p.host
+	}
+	sub, ok := strings.CutSuffix(host, "."+p.host)
+	return ok && sub != ""
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"task-management-system/internal/config"
+)
+
+func TestOriginPattern_Match(t *testing.T) {
+	tests := []struct {
+		pattern string
+		origin  string
+		want    bool
+	}{
+		{"https://app.example.com", "https://app.example.com", true},
+		{"https://app.example.com", "https://APP.example.com", true},
+		{"https://app.example.com", "http://app.example.com", false},
+		{"https://app.example.com", "https://app.example.com:8443", false},
+		{"http://localhost:3000", "http://localhost:3000", true},
+		{"http://localhost:3000", "http://localhost:3001", false},
+		{"https://*.example.com", "https://a.example.com", true},
+		{"https://*.example.com", "https://a.b.example.com", true},
+		{"https://*.example.com", "https://example.com", false},
+		{"https://*.example.com", "https://evil-example.com", false},
+		{"https://*.example.com", "https://example.com.evil.com", false},
+		{"https://*.example.com", "null", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.origin, func(t *testing.T) {
+			pattern, ok := parseOriginPattern(tt.pattern)
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, pattern.match(tt.origin))
+		})
+	}
+}
+
+func TestCorsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(CorsMiddleware(&config.CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowCredentials: true,
+		Routes: []config.CORSRouteConfig{
+			{PathPrefix: "/public", AllowedOrigins: []string{"*"}},
+		},
+	}))
+	r.GET("/tasks", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/public/info", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		origin          string
+		wantStatus      int
+		wantAllowOrigin string
+		wantCredentials string
+	}{
+		{"白名单来源原样返回", http.MethodGet, "/tasks", "https://app.example.com", http.StatusOK, "https://app.example.com", "true"},
+		{"非白名单来源不返回CORS头", http.MethodGet, "/tasks", "https://evil.com", http.StatusOK, "", ""},
+		{"没有Origin的请求", http.MethodGet, "/tasks", "", http.StatusOK, "", ""},
+		{"预检请求", http.MethodOptions, "/tasks", "https://app.example.com", http.StatusNoContent, "https://app.example.com", "true"},
+		{"非白名单来源的预检请求被拒绝", http.MethodOptions, "/tasks", "https://evil.com", http.StatusForbidden, "", ""},
+		{"按路径覆盖允许任意来源", http.MethodGet, "/public/info", "https://evil.com", http.StatusOK, "*", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, tt.wantAllowOrigin, w.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, tt.wantCredentials, w.Header().Get("Access-Control-Allow-Credentials"))
+			assert.Equal(t, "Origin", w.Header().Get("Vary"))
+			if tt.wantStatus == http.StatusNoContent {
+				assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "X-User-ID")
+			}
+		})
+	}
+}
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(SecurityHeadersMiddleware(&config.SecurityHeadersConfig{HSTSMaxAge: 31536000, HSTSIncludeSubdomains: true}))
+	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"), "HTTP 请求不发送 HSTS")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "max-age=31536000; includeSubDomains", w.Header().Get("Strict-Transport-Security"))
+}
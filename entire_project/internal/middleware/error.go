@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/models"
+)
+
+// internalErrorMessage 内部错误返回给客户端的提示，详细原因只记录在日志中
+const internalErrorMessage = "服务器内部错误"
+
+// ErrorMiddleware 统一错误处理中间件
+// 学习要点：处理器和中间件只需 c.Error(err) 记录错误，
+// 这里根据错误类别统一决定HTTP状态码、错误码和提示信息
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		status := apperr.HTTPStatus(err)
+		message := err.Error()
+		if apperr.IsInternal(err) {
+			slog.ErrorContext(c.Request.Context(), "请求处理失败",
+				"method", c.Request.Method, "path", c.Request.URL.Path, "error", err)
+			message = internalErrorMessage
+		}
+
+		c.JSON(status, models.NewErrorResponse(status, apperr.Code(err), message))
+	}
+}
+
+// abortWithError 记录错误并中断请求链，由 ErrorMiddleware 输出响应
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
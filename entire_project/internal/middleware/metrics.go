@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"task-management-system/pkg/metrics"
+)
+
+// unmatchedRoute 未匹配到路由（404）时使用的 route 标签
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware HTTP指标中间件
+// 学习要点：c.FullPath() 返回路由模板（如 /api/v1/tasks/:id），
+// 同一接口不同ID的请求会汇总到同一条时间序列
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		metrics.HTTPRequestsInFlight.Inc()
+		defer metrics.HTTPRequestsInFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequestsTotal.WithLabelValues(c.Request.Method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/config"
+	"task-management-system/pkg/cache"
+	"task-management-system/pkg/metrics"
+	"task-management-system/pkg/ratelimit"
+)
+
+// errRateLimited 请求过于频繁
+var errRateLimited = apperr.New(apperr.ErrTooManyRequests, "rate_limited", "请求过于频繁，请稍后重试")
+
+// RateLimitMiddleware 限流中间件
+// 学习要点：已登录的请求按用户限流，匿名请求按客户端IP限流；
+// 需要放在 OptionalAuthMiddleware 或 AuthMiddleware 之后才能取到当前用户。
+// 限流存储完全不可用时放行请求（fail open），避免缓存故障导致整个API不可用
+func RateLimitMiddleware(limiter *ratelimit.Limiter, group string, rule config.RateLimitRule) gin.HandlerFunc {
+	limit := ratelimit.Rule{Requests: rule.Requests, Window: rule.GetWindow()}
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		result, err := limiter.Allow(ctx, rateLimitKey(c, group), limit)
+		if err != nil {
+			slog.WarnContext(ctx, "限流检查失败，放行请求", "group", group, "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			metrics.RateLimitRejectedTotal.WithLabelValues(group).Inc()
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			abortWithError(c, errRateLimited)
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateLimitKey 限流计数键：ratelimit:<路由组>:user:<用户ID> 或 ratelimit:<路由组>:ip:<IP>
+func rateLimitKey(c *gin.Context, group string) string {
+	if userID, ok := GetCurrentUserID(c); ok {
+		return fmt.Sprintf("%s%s:user:%d", cache.RateLimitPrefix, group, userID)
+	}
+	return fmt.Sprintf("%s%s:ip:%s", cache.RateLimitPrefix, group, c.ClientIP())
+}
+
+// ceilSeconds 向上取整到秒，至少为1秒
+func ceilSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
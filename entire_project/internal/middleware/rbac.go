@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"task-management-system/internal/models"
+)
+
+// RequirePermission 权限校验中间件
+// 学习要点：必须放在 AuthMiddleware 之后，根据令牌中的角色判断是否拥有权限
+func RequirePermission(permission models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := GetCurrentUserID(c); !ok {
+			abortWithError(c, errNotLoggedIn)
+			return
+		}
+
+		if !models.RoleHasPermission(GetCurrentRole(c), permission) {
+			abortWithError(c, errPermissionDenied)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireRoles 角色校验中间件，当前用户角色在列表中即放行
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := GetCurrentUserID(c); !ok {
+			abortWithError(c, errNotLoggedIn)
+			return
+		}
+
+		current := GetCurrentRole(c)
+		for _, role := range roles {
+			if current == role {
+				c.Next()
+				return
+			}
+		}
+
+		abortWithError(c, errPermissionDenied)
+	}
+}
+
+// AdminAuthMiddleware 管理员认证中间件
+// 学习要点：中间件组合，管理员路由组统一挂载
+func AdminAuthMiddleware() gin.HandlerFunc {
+	return RequireRoles(models.RoleAdmin)
+}
@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"github.com/gin-gonic/gin"
+	"task-management-system/pkg/logger"
+	"task-management-system/pkg/tracing"
 	"task-management-system/pkg/utils"
 )
 
@@ -12,13 +14,18 @@ func RequestIDMiddleware() gin.HandlerFunc {
 		// 尝试从请求头获取请求ID
 		requestID := c.GetHeader("X-Request-ID")
 		
-		// 如果请求头没有提供请求ID，则生成一个新的
+		// 如果请求头没有提供请求ID，优先使用链路追踪ID，便于用同一个ID检索日志和链路
+		if requestID == "" {
+			requestID = tracing.TraceID(c.Request.Context())
+		}
 		if requestID == "" {
 			requestID = utils.GenerateRequestID()
 		}
 		
 		// 设置请求ID到上下文中
-		c.Set("request_id", requestID)
+		// 学习要点：同时写入 c.Request 的 context，服务层用 slog.XxxContext(ctx, ...) 记录的日志会自动带上 request_id
+		c.Set(ContextRequestIDKey, requestID)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
 		
 		// 在响应头中返回请求ID
 		c.Header("X-Request-ID", requestID)
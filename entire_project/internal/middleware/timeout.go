@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"task-management-system/internal/apperr"
+)
+
+// errRequestTimeout 请求处理超时
+var errRequestTimeout = apperr.New(apperr.ErrTimeout, "request_timeout", "请求超时，请稍后重试")
+
+// TimeoutMiddleware 请求超时中间件
+// 学习要点：给请求的 context 设置截止时间，处理器把 c.Request.Context() 传给服务、DAO 和缓存，
+// 超时后 MySQL 和 Redis 操作会被取消；处理器仍在当前 goroutine 中执行，不会并发写响应。
+// 可以挂在路由组上，也可以挂在单个路由上；嵌套使用时以更早的截止时间为准
+func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+
+		original := c.Writer
+		writer := &timeoutWriter{ResponseWriter: original, ctx: ctx}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = original
+		if writer.timedOut() {
+			abortWithError(c, errRequestTimeout)
+		}
+	}
+}
+
+// timeoutWriter 超时后丢弃处理器写入的响应
+// 学习要点：截止时间过后处理器写出的任何响应都被丢弃，由错误中间件统一输出504
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx     context.Context
+	timeout bool
+}
+
+// timedOut 截止时间已过且处理器尚未写出响应
+func (w *timeoutWriter) timedOut() bool {
+	if !w.timeout && !w.ResponseWriter.Written() && errors.Is(w.ctx.Err(), context.DeadlineExceeded) {
+		w.timeout = true
+	}
+	return w.timeout
+}
+
+// WriteHeader 超时后忽略处理器设置的状态码
+func (w *timeoutWriter) WriteHeader(code int) {
+	if w.timedOut() {
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// WriteHeaderNow 超时后不再写出状态码
+func (w *timeoutWriter) WriteHeaderNow() {
+	if w.timedOut() {
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write 超时后丢弃响应体
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	if w.timedOut() {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString 超时后丢弃响应体
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	if w.timedOut() {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// 任务动态类型
+// 学习要点：状态变更按流转记录为 started/completed/paused/cancelled/reopened，见 taskTransitions
+const (
+	ActivityActionCreated   = "created"   // 创建任务
+	ActivityActionUpdated   = "updated"   // 修改任务字段
+	ActivityActionStarted   = "started"   // 开始处理任务
+	ActivityActionCompleted = "completed" // 标记任务完成
+	ActivityActionPaused    = "paused"    // 进行中的任务退回待处理
+	ActivityActionCancelled = "cancelled" // 取消任务
+	ActivityActionReopened  = "reopened"  // 重新打开已完成或已取消的任务
+)
+
+// TaskActivity 任务动态
+// 学习要点：只追加不修改的操作日志，因此没有 UpdatedAt 和软删除字段；
+// 每条记录对应一个字段的变化，旧值和新值统一保存为字符串
+type TaskActivity struct {
+	ID        uint      `gorm:"primarykey;comment:主键ID" json:"id"`                                     // 主键ID
+	CreatedAt time.Time `gorm:"comment:创建时间" json:"created_at"`                                        // 发生时间
+	TaskID    uint      `gorm:"not null;index;comment:任务ID" json:"task_id"`                            // 所属任务ID
+	UserID    uint      `gorm:"not null;comment:操作用户ID" json:"user_id"`                                // 操作用户ID
+	Action    string    `gorm:"size:20;not null;comment:动态类型 created/updated/completed" json:"action"` // 动态类型
+	Field     string    `gorm:"size:50;comment:变更字段" json:"field,omitempty"`                           // 变更字段（创建任务时为空）
+	OldValue  string    `gorm:"type:text;comment:旧值" json:"old_value"`                                 // 旧值
+	NewValue  string    `gorm:"type:text;comment:新值" json:"new_value"`                                 // 新值
+}
+
+// TableName 自定义表名
+func (TaskActivity) TableName() string {
+	return "task_activities"
+}
@@ -0,0 +1,12 @@
+package models
+
+// LoginRequest 登录请求
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"` // 用户名（必填）
+	Password string `json:"password" binding:"required"` // 密码（必填）
+}
+
+// RefreshTokenRequest 刷新令牌请求
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"` // 刷新令牌（必填）
+}
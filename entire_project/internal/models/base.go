@@ -25,9 +25,10 @@ type TableName interface {
 // Response 统一响应结构
 // 学习要点：API 响应标准化
 type Response struct {
-	Code    int         `json:"code"`    // 状态码：200成功，其他失败
-	Message string      `json:"message"` // 响应消息
-	Data    interface{} `json:"data"`    // 响应数据
+	Code      int         `json:"code"`                 // 状态码：200成功，其他与HTTP状态码一致
+	Message   string      `json:"message"`              // 响应消息
+	Data      interface{} `json:"data"`                 // 响应数据
+	ErrorCode string      `json:"error_code,omitempty"` // 机器可读的错误码（仅失败时返回）
 }
 
 // PageInfo 分页信息
@@ -59,6 +60,11 @@ func NewSuccessResponse(data interface{}) Response {
 }
 
 // NewErrorResponse 创建错误响应
-func NewErrorResponse(message string) Response {
-	return NewResponse(500, message, nil)
+// 学习要点：code 与HTTP状态码保持一致，errorCode 供客户端判断具体的失败原因
+func NewErrorResponse(code int, errorCode, message string) Response {
+	return Response{
+		Code:      code,
+		Message:   message,
+		ErrorCode: errorCode,
+	}
 }
\ No newline at end of file
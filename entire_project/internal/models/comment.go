@@ -0,0 +1,28 @@
+package models
+
+// TaskComment 任务评论
+// 学习要点：一对多关系，评论正文保存原始 Markdown，由前端负责渲染
+type TaskComment struct {
+	BaseModel
+	TaskID uint   `gorm:"not null;index;comment:任务ID" json:"task_id"`            // 所属任务ID
+	UserID uint   `gorm:"not null;comment:评论作者ID" json:"user_id"`                // 评论作者ID
+	Body   string `gorm:"type:text;not null;comment:评论内容(Markdown)" json:"body"` // 评论内容
+
+	// 关联关系
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"` // 多对一：评论作者
+}
+
+// TableName 自定义表名
+func (TaskComment) TableName() string {
+	return "task_comments"
+}
+
+// CommentCreateRequest 发表评论请求
+type CommentCreateRequest struct {
+	Body string `json:"body" binding:"required,max=10000"` // 评论内容（Markdown，必填）
+}
+
+// CommentUpdateRequest 编辑评论请求
+type CommentUpdateRequest struct {
+	Body string `json:"body" binding:"required,max=10000"` // 评论内容（Markdown，必填）
+}
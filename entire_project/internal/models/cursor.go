@@ -0,0 +1,85 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// 游标分页默认值
+const (
+	DefaultCursorLimit = 20  // 默认每页数量
+	MaxCursorLimit     = 100 // 每页最大数量
+)
+
+// cursorPrefix 游标内容前缀，用于识别格式，以后调整游标内容时可以换前缀兼容
+const cursorPrefix = "id:"
+
+// ErrInvalidCursor 游标格式错误
+var ErrInvalidCursor = errors.New("游标格式错误")
+
+// CursorQuery 游标分页查询参数
+// 学习要点：游标分页用上一页最后一条记录的ID定位下一页，
+// 不受新插入数据影响，深翻页时也不需要 OFFSET 扫描
+type CursorQuery struct {
+	Cursor string `form:"cursor"` // 上一页返回的 next_cursor，为空表示第一页
+	Limit  int    `form:"limit"`  // 每页数量
+}
+
+// GetLimit 获取每页数量，未设置或超出范围时使用默认值
+func (q CursorQuery) GetLimit() int {
+	if q.Limit <= 0 || q.Limit > MaxCursorLimit {
+		return DefaultCursorLimit
+	}
+	return q.Limit
+}
+
+// CursorPage 游标分页结果
+type CursorPage struct {
+	List       interface{} `json:"list"`                  // 数据列表
+	NextCursor string      `json:"next_cursor,omitempty"` // 下一页游标
+	HasMore    bool        `json:"has_more"`              // 是否还有下一页
+}
+
+// NewCursorPage 根据多查询一条的结果构建分页
+// 学习要点：DAO 多查一条记录（limit+1）就能知道是否还有下一页，不需要额外的 COUNT 查询
+func NewCursorPage[T any](items []T, limit int, id func(T) uint) CursorPage {
+	if items == nil {
+		items = []T{} // 空列表返回 [] 而不是 null
+	}
+	page := CursorPage{List: items}
+	if len(items) > limit {
+		items = items[:limit]
+		page.List = items
+		page.HasMore = true
+		page.NextCursor = EncodeCursor(id(items[len(items)-1]))
+	}
+	return page
+}
+
+// EncodeCursor 把记录ID编码为不透明的游标字符串
+// 学习要点：客户端只需原样回传游标，不应依赖其内容
+func EncodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatUint(uint64(id), 10)))
+}
+
+// DecodeCursor 解析游标，空字符串表示第一页，返回 0
+func DecodeCursor(cursor string) (uint, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	value, ok := strings.CutPrefix(string(raw), cursorPrefix)
+	if !ok {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		return 0, ErrInvalidCursor
+	}
+	return uint(id), nil
+}
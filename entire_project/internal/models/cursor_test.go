@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	id, err := DecodeCursor(EncodeCursor(42))
+	require.NoError(t, err)
+	assert.Equal(t, uint(42), id)
+
+	id, err = DecodeCursor("")
+	require.NoError(t, err)
+	assert.Zero(t, id, "空游标表示第一页")
+
+	for _, cursor := range []string{"42", "!!!", EncodeCursor(0), "aWQ6YWJj"} {
+		_, err := DecodeCursor(cursor)
+		assert.ErrorIs(t, err, ErrInvalidCursor, cursor)
+	}
+}
+
+func TestNewCursorPage(t *testing.T) {
+	id := func(v uint) uint { return v }
+
+	page := NewCursorPage([]uint{9, 8, 7}, 2, id)
+	assert.Equal(t, []uint{9, 8}, page.List)
+	assert.True(t, page.HasMore)
+	assert.Equal(t, EncodeCursor(8), page.NextCursor, "游标指向本页最后一条")
+
+	page = NewCursorPage([]uint{9, 8}, 2, id)
+	assert.False(t, page.HasMore)
+	assert.Empty(t, page.NextCursor)
+
+	page = NewCursorPage[uint](nil, 2, id)
+	assert.Equal(t, []uint{}, page.List)
+}
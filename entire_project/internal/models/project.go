@@ -0,0 +1,97 @@
+package models
+
+import "time"
+
+// 项目角色常量
+// 学习要点：项目内的角色与全局角色（admin/member/viewer）相互独立，
+// 同一个用户可以在一个项目中是维护者，在另一个项目中只能查看
+const (
+	ProjectRoleOwner      = "owner"      // 所有者：创建项目的用户，可以删除项目
+	ProjectRoleMaintainer = "maintainer" // 维护者：管理项目信息、成员、标签，可以修改项目中的所有任务
+	ProjectRoleMember     = "member"     // 成员：在项目中创建和修改任务
+	ProjectRoleViewer     = "viewer"     // 访客：只能查看项目和任务
+)
+
+// 项目权限常量
+const (
+	PermissionProjectManage Permission = "project:manage" // 修改项目信息，管理成员和项目标签
+	PermissionProjectDelete Permission = "project:delete" // 删除项目
+)
+
+// projectRolePermissions 项目角色与权限的对应关系
+var projectRolePermissions = map[string][]Permission{
+	ProjectRoleOwner:      {PermissionTaskRead, PermissionTaskWrite, PermissionProjectManage, PermissionProjectDelete},
+	ProjectRoleMaintainer: {PermissionTaskRead, PermissionTaskWrite, PermissionProjectManage},
+	ProjectRoleMember:     {PermissionTaskRead, PermissionTaskWrite},
+	ProjectRoleViewer:     {PermissionTaskRead},
+}
+
+// ProjectRoleHasPermission 判断项目角色是否拥有指定权限
+func ProjectRoleHasPermission(role string, permission Permission) bool {
+	for _, p := range projectRolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Project 项目模型
+// 学习要点：项目拥有任务、成员和自己的标签集合，任务的 ProjectID 为空表示不属于任何项目
+type Project struct {
+	BaseModel
+	Name        string `gorm:"size:100;not null;comment:项目名称" json:"name"`     // 项目名称
+	Description string `gorm:"type:text;comment:项目描述" json:"description"`      // 项目描述
+	OwnerID     uint   `gorm:"not null;index;comment:所有者用户ID" json:"owner_id"` // 所有者用户ID
+
+	// 关联关系
+	Owner   User            `gorm:"foreignKey:OwnerID" json:"owner,omitempty"`     // 多对一：项目所有者
+	Members []ProjectMember `gorm:"foreignKey:ProjectID" json:"members,omitempty"` // 一对多：项目成员
+}
+
+// TableName 自定义表名
+func (Project) TableName() string {
+	return "projects"
+}
+
+// ProjectMember 项目成员
+// 学习要点：带额外字段（角色）的多对多关系，用独立模型表示关联表
+type ProjectMember struct {
+	ProjectID uint      `gorm:"primaryKey;autoIncrement:false;comment:项目ID" json:"project_id"`            // 项目ID
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false;index;comment:用户ID" json:"user_id"`         // 用户ID
+	Role      string    `gorm:"size:20;not null;comment:项目角色 owner/maintainer/member/viewer" json:"role"` // 项目角色
+	CreatedAt time.Time `gorm:"comment:加入时间" json:"created_at"`                                           // 加入时间
+	UpdatedAt time.Time `gorm:"comment:更新时间" json:"updated_at"`                                           // 更新时间
+
+	// 关联关系
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"` // 多对一：成员对应的用户
+}
+
+// TableName 自定义表名
+func (ProjectMember) TableName() string {
+	return "project_members"
+}
+
+// ProjectCreateRequest 创建项目请求
+type ProjectCreateRequest struct {
+	Name        string `json:"name" binding:"required,max=100"` // 项目名称（必填）
+	Description string `json:"description"`                     // 项目描述
+}
+
+// ProjectUpdateRequest 更新项目请求
+type ProjectUpdateRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=100"` // 项目名称
+	Description *string `json:"description"`                            // 项目描述
+}
+
+// ProjectMemberRequest 添加项目成员请求
+// 学习要点：所有者在创建项目时确定，不能通过成员接口授予
+type ProjectMemberRequest struct {
+	UserID uint   `json:"user_id" binding:"required"`                             // 用户ID
+	Role   string `json:"role" binding:"required,oneof=maintainer member viewer"` // 项目角色
+}
+
+// ProjectMemberUpdateRequest 修改项目成员角色请求
+type ProjectMemberUpdateRequest struct {
+	Role string `json:"role" binding:"required,oneof=maintainer member viewer"` // 项目角色
+}
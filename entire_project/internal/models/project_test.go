@@ -0,0 +1,30 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectRoleHasPermission(t *testing.T) {
+	tests := []struct {
+		role       string
+		permission Permission
+		want       bool
+	}{
+		{ProjectRoleOwner, PermissionProjectDelete, true},
+		{ProjectRoleMaintainer, PermissionProjectManage, true},
+		{ProjectRoleMaintainer, PermissionProjectDelete, false},
+		{ProjectRoleMember, PermissionTaskWrite, true},
+		{ProjectRoleMember, PermissionProjectManage, false},
+		{ProjectRoleViewer, PermissionTaskRead, true},
+		{ProjectRoleViewer, PermissionTaskWrite, false},
+		{"unknown", PermissionTaskRead, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role+"/"+string(tt.permission), func(t *testing.T) {
+			assert.Equal(t, tt.want, ProjectRoleHasPermission(tt.role, tt.permission))
+		})
+	}
+}
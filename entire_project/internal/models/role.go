@@ -0,0 +1,55 @@
+package models
+
+// 用户角色常量
+// 学习要点：基于角色的访问控制（RBAC），角色决定用户拥有哪些权限
+const (
+	RoleAdmin  = "admin"  // 管理员：拥有全部权限
+	RoleMember = "member" // 普通成员：可以读写自己的任务
+	RoleViewer = "viewer" // 只读成员：只能查看
+)
+
+// Permission 权限标识
+type Permission string
+
+// 权限常量
+const (
+	PermissionTaskRead   Permission = "task:read"   // 查看任务
+	PermissionTaskWrite  Permission = "task:write"  // 创建、修改、删除任务
+	PermissionUserManage Permission = "user:manage" // 管理用户（禁用、启用、强制下线、修改角色）
+	PermissionStatsView  Permission = "stats:view"  // 查看全局统计
+	PermissionTagManage  Permission = "tag:manage"  // 修改、删除全局标签
+)
+
+// rolePermissions 角色与权限的对应关系
+// 学习要点：权限集中定义在一张表里，新增角色时只需要修改这里
+var rolePermissions = map[string][]Permission{
+	RoleAdmin:  {PermissionTaskRead, PermissionTaskWrite, PermissionUserManage, PermissionStatsView, PermissionTagManage},
+	RoleMember: {PermissionTaskRead, PermissionTaskWrite},
+	RoleViewer: {PermissionTaskRead},
+}
+
+// IsValidRole 判断角色是否合法
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// RoleHasPermission 判断角色是否拥有指定权限
+func RoleHasPermission(role string, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateRoleRequest 修改用户角色请求
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin member viewer"` // 角色
+}
+
+// BatchUserIDsRequest 批量用户ID请求
+type BatchUserIDsRequest struct {
+	UserIDs []uint `json:"user_ids" binding:"required,min=1"` // 用户ID列表
+}
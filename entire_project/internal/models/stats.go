@@ -0,0 +1,27 @@
+package models
+
+// UserStats 用户统计（管理员仪表盘）
+type UserStats struct {
+	Total          int64            `json:"total"`            // 用户总数
+	ByStatus       map[string]int64 `json:"by_status"`        // 按状态统计：active/disabled
+	ByRole         map[string]int64 `json:"by_role"`          // 按角色统计
+	ActiveUsers    int64            `json:"active_users"`     // 最近N天登录过的正常用户数
+	ActiveDays     int              `json:"active_days"`      // 活跃统计的天数N
+	UsersWithTasks int              `json:"users_with_tasks"` // 有任务的用户数
+}
+
+// TaskStats 任务统计（管理员仪表盘）
+type TaskStats struct {
+	Total           int64            `json:"total"`            // 任务总数
+	ByStatus        map[string]int64 `json:"by_status"`        // 按状态统计
+	ByPriority      map[string]int64 `json:"by_priority"`      // 按优先级统计
+	Overdue         int64            `json:"overdue"`          // 过期未完成任务数
+	CompletionTrend []DailyCount     `json:"completion_trend"` // 每日完成数量
+	TrendDays       int              `json:"trend_days"`       // 完成趋势的天数
+}
+
+// DailyCount 每日计数
+type DailyCount struct {
+	Date  string `json:"date"`  // 日期（YYYY-MM-DD）
+	Count int64  `json:"count"` // 数量
+}
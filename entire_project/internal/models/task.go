@@ -25,16 +25,34 @@ type Task struct {
 	BaseModel
 	Title       string     `gorm:"size:200;not null;comment:任务标题" json:"title"`                    // 任务标题
 	Description string     `gorm:"type:text;comment:任务描述" json:"description"`                      // 任务描述
-	Status      int        `gorm:"default:0;comment:任务状态 0-待处理 1-进行中 2-已完成 3-已取消" json:"status"`   // 任务状态
+	Status      int        `gorm:"default:0;index;comment:任务状态 0-待处理 1-进行中 2-已完成 3-已取消" json:"status"`   // 任务状态
 	Priority    int        `gorm:"default:2;comment:优先级 1-低 2-中 3-高 4-紧急" json:"priority"`        // 优先级
 	StartTime   *time.Time `gorm:"comment:开始时间" json:"start_time"`                                 // 开始时间
 	EndTime     *time.Time `gorm:"comment:结束时间" json:"end_time"`                                   // 结束时间
-	DueDate     *time.Time `gorm:"comment:截止日期" json:"due_date"`                                   // 截止日期
-	UserID      uint       `gorm:"not null;comment:创建用户ID" json:"user_id"`                        // 创建用户ID（外键）
+	DueDate     *time.Time `gorm:"index;comment:截止日期" json:"due_date"`                                   // 截止日期
+	UserID      uint       `gorm:"not null;index;comment:创建用户ID" json:"user_id"`                        // 创建用户ID（外键）
+	ProjectID   *uint      `gorm:"index;comment:所属项目ID" json:"project_id"`                              // 所属项目ID（为空表示不属于任何项目）
+	ParentID    *uint      `gorm:"index;comment:父任务ID" json:"parent_id"`                                // 父任务ID（为空表示顶层任务）
 	
 	// 关联关系
-	User User    `gorm:"foreignKey:UserID;comment:任务创建者" json:"user,omitempty"`        // 多对一：任务属于一个用户
-	Tags []Tag   `gorm:"many2many:task_tags;comment:任务标签" json:"tags,omitempty"`      // 多对多：任务可以有多个标签
+	User      User   `gorm:"foreignKey:UserID;comment:任务创建者" json:"user,omitempty"`               // 多对一：任务属于一个用户（创建者）
+	Tags      []Tag  `gorm:"many2many:task_tags;comment:任务标签" json:"tags,omitempty"`                // 多对多：任务可以有多个标签
+	Assignees []User `gorm:"many2many:task_assignees;comment:任务负责人" json:"assignees,omitempty"` // 多对多：负责人可以修改任务
+	Watchers  []User `gorm:"many2many:task_watchers;comment:任务关注者" json:"watchers,omitempty"`   // 多对多：关注者只能查看任务
+	Subtasks  []Task `gorm:"foreignKey:ParentID" json:"subtasks,omitempty"`                           // 一对多：子任务
+	BlockedBy []Task `gorm:"many2many:task_dependencies;joinForeignKey:TaskID;joinReferences:BlockerID" json:"blocked_by,omitempty"` // 多对多（自关联）：阻塞本任务的前置任务
+	
+	// 计算字段，不存储到数据库
+	Progress *TaskProgress `gorm:"-" json:"progress,omitempty"` // 子任务进度（没有子任务时不返回）
+}
+
+// TaskProgress 子任务进度汇总
+// 学习要点：已取消的子任务不计入总数；所有子任务完成后提示可以完成父任务，但不自动修改父任务状态
+type TaskProgress struct {
+	Total           int  `json:"total"`            // 子任务数（不含已取消）
+	Completed       int  `json:"completed"`        // 已完成的子任务数
+	Percent         int  `json:"percent"`          // 完成百分比
+	SuggestComplete bool `json:"suggest_complete"` // 子任务已全部完成，建议完成父任务
 }
 
 // TableName 自定义表名
@@ -42,12 +60,55 @@ func (Task) TableName() string {
 	return "tasks"
 }
 
+// IsClosed 判断任务是否已经结束（已完成或已取消）
+func (t *Task) IsClosed() bool {
+	return t.Status == TaskStatusCompleted || t.Status == TaskStatusCancelled
+}
+
+// SubtaskProgress 根据已加载的子任务汇总进度，没有子任务时返回 nil
+func (t *Task) SubtaskProgress() *TaskProgress {
+	if len(t.Subtasks) == 0 {
+		return nil
+	}
+
+	progress := &TaskProgress{}
+	for _, subtask := range t.Subtasks {
+		switch subtask.Status {
+		case TaskStatusCancelled:
+			continue
+		case TaskStatusCompleted:
+			progress.Completed++
+		}
+		progress.Total++
+	}
+	if progress.Total > 0 {
+		progress.Percent = progress.Completed * 100 / progress.Total
+	}
+	progress.SuggestComplete = progress.Total > 0 && progress.Completed == progress.Total && !t.IsClosed()
+	return progress
+}
+
+// TaskDependency 任务依赖关系：BlockerID 对应的任务完成前，TaskID 对应的任务不能开始
+// 学习要点：自关联多对多的关联表，joinForeignKey/joinReferences 指定两列的列名
+type TaskDependency struct {
+	TaskID    uint `gorm:"primaryKey;autoIncrement:false"`       // 被阻塞的任务ID
+	BlockerID uint `gorm:"primaryKey;autoIncrement:false;index"` // 前置任务ID
+}
+
+// TableName 自定义表名
+func (TaskDependency) TableName() string {
+	return "task_dependencies"
+}
+
 // Tag 标签模型
-// 学习要点：标签系统设计，多对多关系
+// 学习要点：标签系统设计，多对多关系；MySQL 唯一索引不约束 NULL，
+// 用生成列 ProjectKey 把全局标签的 project_id 统一为 0，保证全局标签名称也唯一
 type Tag struct {
 	BaseModel
-	Name  string `gorm:"uniqueIndex;size:50;not null;comment:标签名称" json:"name"`  // 标签名称（唯一索引）
-	Color string `gorm:"size:7;comment:标签颜色" json:"color"`                      // 标签颜色（十六进制）
+	Name       string `gorm:"uniqueIndex:idx_tags_project_key_name,priority:2;size:50;not null;comment:标签名称" json:"name"` // 标签名称（同一项目内唯一，全局标签之间唯一）
+	Color      string `gorm:"size:7;comment:标签颜色" json:"color"`                                                         // 标签颜色（十六进制）
+	ProjectID  *uint  `gorm:"index;comment:所属项目ID" json:"project_id"`                                                   // 所属项目ID（为空表示全局标签）
+	ProjectKey uint   `gorm:"->;type:bigint unsigned AS (COALESCE(project_id, 0)) STORED;uniqueIndex:idx_tags_project_key_name,priority:1;comment:所属项目ID（全局标签为0，用于唯一索引）" json:"-"` // 生成列，只读
 	
 	// 关联关系
 	Tasks []Task `gorm:"many2many:task_tags;comment:标签的任务" json:"tasks,omitempty"` // 多对多：标签可以属于多个任务
@@ -65,6 +126,8 @@ type TaskCreateRequest struct {
 	Priority    int        `json:"priority" binding:"min=1,max=4"`         // 优先级（1-4）
 	DueDate     *time.Time `json:"due_date"`                               // 截止日期
 	TagIDs      []uint     `json:"tag_ids"`                                // 标签ID列表
+	ProjectID   *uint      `json:"project_id"`                             // 所属项目ID（需要是项目成员）
+	ParentID    *uint      `json:"parent_id"`                              // 父任务ID（需要能修改父任务，且与父任务属于同一项目）
 }
 
 // TaskUpdateRequest 更新任务请求
@@ -79,15 +142,32 @@ type TaskUpdateRequest struct {
 	TagIDs      []uint     `json:"tag_ids"`                                // 标签ID列表
 }
 
+// TaskAssignRequest 指派任务负责人请求
+type TaskAssignRequest struct {
+	UserIDs []uint `json:"user_ids" binding:"required,min=1"` // 负责人用户ID列表
+}
+
+// TaskDependencyRequest 添加任务依赖请求
+type TaskDependencyRequest struct {
+	BlockerID uint `json:"blocker_id" binding:"required"` // 前置任务ID（完成前本任务不能开始）
+}
+
 // TaskQueryRequest 任务查询请求
 type TaskQueryRequest struct {
-	Status   *int   `form:"status"`    // 任务状态
-	Priority *int   `form:"priority"`  // 优先级
-	TagID    *uint  `form:"tag_id"`    // 标签ID
-	UserID   *uint  `form:"user_id"`   // 用户ID
-	Keyword  string `form:"keyword"`   // 关键词搜索
-	Page     int    `form:"page"`      // 页码
-	PageSize int    `form:"page_size"` // 每页数量
+	Status       *int   `form:"status"`         // 任务状态
+	Priority     *int   `form:"priority"`       // 优先级
+	TagID        *uint  `form:"tag_id"`         // 标签ID
+	UserID       *uint  `form:"user_id"`        // 用户ID（创建者）
+	ProjectID    *uint  `form:"project_id"`     // 项目ID（需要是项目成员）
+	AssigneeID   *uint  `form:"assignee_id"`    // 负责人ID
+	AssignedToMe bool   `form:"assigned_to_me"` // 只看指派给当前用户的任务（需登录）
+	Keyword      string `form:"keyword"`        // 关键词搜索
+	Page         int    `form:"page"`           // 页码
+	PageSize     int    `form:"page_size"`      // 每页数量
+
+	// 以下字段由处理器根据当前登录用户设置，不从查询参数绑定
+	ViewerID        uint `form:"-"` // 当前用户ID，0 表示未登录
+	ViewAllProjects bool `form:"-"` // 是否可以查看所有项目的任务（管理员）
 }
 
 // TagCreateRequest 创建标签请求
@@ -96,10 +176,77 @@ type TagCreateRequest struct {
 	Color string `json:"color" binding:"omitempty,len=7"` // 标签颜色（7位十六进制）
 }
 
+// TagUpdateRequest 更新标签请求
+type TagUpdateRequest struct {
+	Name  *string `json:"name" binding:"omitempty,min=1,max=50"` // 标签名称
+	Color *string `json:"color" binding:"omitempty,len=7"`       // 标签颜色（7位十六进制）
+}
+
+// TagResponse 标签响应（包含使用次数）
+type TagResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	ProjectID *uint     `json:"project_id,omitempty"` // 所属项目ID（全局标签不返回）
+	TaskCount int64     `json:"task_count"`           // 使用该标签的任务数
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ToResponse 转换为响应格式
+func (t *Tag) ToResponse(taskCount int64) TagResponse {
+	return TagResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		Color:     t.Color,
+		ProjectID: t.ProjectID,
+		TaskCount: taskCount,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+// StatusKey 获取状态的英文标识（用于统计结果和缓存键）
+func StatusKey(status int) string {
+	switch status {
+	case TaskStatusPending:
+		return "pending"
+	case TaskStatusInProgress:
+		return "in_progress"
+	case TaskStatusCompleted:
+		return "completed"
+	case TaskStatusCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// PriorityKey 获取优先级的英文标识
+func PriorityKey(priority int) string {
+	switch priority {
+	case TaskPriorityLow:
+		return "low"
+	case TaskPriorityMedium:
+		return "medium"
+	case TaskPriorityHigh:
+		return "high"
+	case TaskPriorityUrgent:
+		return "urgent"
+	default:
+		return "unknown"
+	}
+}
+
 // GetStatusText 获取状态文本
 // 学习要点：枚举值的文本转换
 func (t *Task) GetStatusText() string {
-	switch t.Status {
+	return StatusText(t.Status)
+}
+
+// StatusText 获取状态的中文名称（用于错误信息等面向用户的文本）
+func StatusText(status int) string {
+	switch status {
 	case TaskStatusPending:
 		return "待处理"
 	case TaskStatusInProgress:
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTask_SubtaskProgress(t *testing.T) {
+	subtasks := func(statuses ...int) []Task {
+		tasks := make([]Task, 0, len(statuses))
+		for _, status := range statuses {
+			tasks = append(tasks, Task{Status: status})
+		}
+		return tasks
+	}
+
+	tests := []struct {
+		name   string
+		parent Task
+		want   *TaskProgress
+	}{
+		{"没有子任务", Task{}, nil},
+		{"部分完成", Task{Subtasks: subtasks(TaskStatusCompleted, TaskStatusInProgress, TaskStatusPending)},
+			&TaskProgress{Total: 3, Completed: 1, Percent: 33}},
+		{"已取消的子任务不计入", Task{Subtasks: subtasks(TaskStatusCompleted, TaskStatusCancelled)},
+			&TaskProgress{Total: 1, Completed: 1, Percent: 100, SuggestComplete: true}},
+		{"父任务已完成不再提示", Task{Status: TaskStatusCompleted, Subtasks: subtasks(TaskStatusCompleted)},
+			&TaskProgress{Total: 1, Completed: 1, Percent: 100}},
+		{"子任务全部取消", Task{Subtasks: subtasks(TaskStatusCancelled)}, &TaskProgress{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.parent.SubtaskProgress())
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+// TaskTransition 任务状态流转
+type TaskTransition struct {
+	From   int    // 当前状态
+	To     int    // 目标状态
+	Action string // 记录到任务动态的动作
+}
+
+// taskTransitions 任务状态流转表，不在表中的状态变更都是非法的
+// 学习要点：用数据而不是分散的 if 判断描述状态机，允许哪些流转一目了然，也方便测试逐项核对；
+// 正常流程为 待处理 → 进行中 → 已完成，任何未取消的任务都可以取消，
+// 已完成的任务重新打开回到进行中，已取消的任务恢复为待处理
+var taskTransitions = []TaskTransition{
+	{TaskStatusPending, TaskStatusInProgress, ActivityActionStarted},
+	{TaskStatusPending, TaskStatusCancelled, ActivityActionCancelled},
+	{TaskStatusInProgress, TaskStatusCompleted, ActivityActionCompleted},
+	{TaskStatusInProgress, TaskStatusPending, ActivityActionPaused},
+	{TaskStatusInProgress, TaskStatusCancelled, ActivityActionCancelled},
+	{TaskStatusCompleted, TaskStatusInProgress, ActivityActionReopened},
+	{TaskStatusCompleted, TaskStatusCancelled, ActivityActionCancelled},
+	{TaskStatusCancelled, TaskStatusPending, ActivityActionReopened},
+}
+
+// FindTaskTransition 查找从 from 到 to 的状态流转，不允许时返回 false；状态不变不算流转
+func FindTaskTransition(from, to int) (TaskTransition, bool) {
+	for _, transition := range taskTransitions {
+		if transition.From == from && transition.To == to {
+			return transition, true
+		}
+	}
+	return TaskTransition{}, false
+}
+
+// IsReopen 判断是否为重新打开已结束（完成或取消）的任务
+func (t TaskTransition) IsReopen() bool {
+	return t.Action == ActivityActionReopened
+}
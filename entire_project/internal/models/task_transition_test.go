@@ -0,0 +1,52 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindTaskTransition(t *testing.T) {
+	const (
+		pending    = TaskStatusPending
+		inProgress = TaskStatusInProgress
+		completed  = TaskStatusCompleted
+		cancelled  = TaskStatusCancelled
+	)
+
+	// 逐项列出所有状态组合，新增或删除流转时需要同步修改此表
+	tests := []struct {
+		from, to   int
+		wantAction string // 为空表示不允许
+	}{
+		{pending, pending, ""},
+		{pending, inProgress, ActivityActionStarted},
+		{pending, completed, ""},
+		{pending, cancelled, ActivityActionCancelled},
+
+		{inProgress, pending, ActivityActionPaused},
+		{inProgress, inProgress, ""},
+		{inProgress, completed, ActivityActionCompleted},
+		{inProgress, cancelled, ActivityActionCancelled},
+
+		{completed, pending, ""},
+		{completed, inProgress, ActivityActionReopened},
+		{completed, completed, ""},
+		{completed, cancelled, ActivityActionCancelled},
+
+		{cancelled, pending, ActivityActionReopened},
+		{cancelled, inProgress, ""},
+		{cancelled, completed, ""},
+		{cancelled, cancelled, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s->%s", StatusKey(tt.from), StatusKey(tt.to)), func(t *testing.T) {
+			transition, ok := FindTaskTransition(tt.from, tt.to)
+			assert.Equal(t, tt.wantAction != "", ok)
+			assert.Equal(t, tt.wantAction, transition.Action)
+			assert.Equal(t, tt.wantAction == ActivityActionReopened, transition.IsReopen())
+		})
+	}
+}
@@ -13,6 +13,7 @@ type User struct {
 	Avatar      string    `gorm:"size:255;comment:头像" json:"avatar"`                              // 头像URL
 	Phone       string    `gorm:"size:20;comment:手机号" json:"phone"`                               // 手机号
 	Status      int       `gorm:"default:1;comment:状态 1-正常 0-禁用" json:"status"`                   // 状态
+	Role        string    `gorm:"size:20;default:member;comment:角色 admin/member/viewer" json:"role"` // 角色
 	LastLoginAt *time.Time `gorm:"comment:最后登录时间" json:"last_login_at"`                           // 最后登录时间
 	
 	// 关联关系
@@ -41,6 +42,12 @@ type UserUpdateRequest struct {
 	Phone    string `json:"phone" binding:"max=20"`    // 手机号
 }
 
+// ChangePasswordRequest 修改密码请求
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`       // 原密码（必填）
+	NewPassword string `json:"new_password" binding:"required,min=6"` // 新密码（必填，最少6位）
+}
+
 // UserResponse 用户响应（不包含敏感信息）
 type UserResponse struct {
 	ID          uint       `json:"id"`
@@ -50,6 +57,7 @@ type UserResponse struct {
 	Avatar      string     `json:"avatar"`
 	Phone       string     `json:"phone"`
 	Status      int        `json:"status"`
+	Role        string     `json:"role"`
 	LastLoginAt *time.Time `json:"last_login_at"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
@@ -66,6 +74,7 @@ func (u *User) ToResponse() UserResponse {
 		Avatar:      u.Avatar,
 		Phone:       u.Phone,
 		Status:      u.Status,
+		Role:        u.Role,
 		LastLoginAt: u.LastLoginAt,
 		CreatedAt:   u.CreatedAt,
 		UpdatedAt:   u.UpdatedAt,
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/auth"
+	"task-management-system/internal/config"
+	"task-management-system/internal/dao"
+	"task-management-system/internal/models"
+	"task-management-system/pkg/cache"
+	"task-management-system/pkg/utils"
+)
+
+// 认证相关错误
+// 学习要点：用预定义错误区分失败原因，处理器据此返回不同的HTTP状态码
+var (
+	ErrInvalidCredentials = apperr.Unauthorized("invalid_credentials", "用户名或密码错误")
+	ErrUserDisabled       = apperr.Forbidden("user_disabled", "用户已被禁用")
+	ErrTokenRevoked       = apperr.Unauthorized("token_revoked", "刷新令牌已失效，请重新登录")
+)
+
+// dummyPasswordHash 用户不存在时用于比对的哈希
+// 学习要点：用户不存在时也做一次 bcrypt 比对，让响应时间与密码错误一致，避免通过耗时判断账号是否存在
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hashed, err := utils.HashPassword("dummy-password")
+	if err != nil {
+		panic(err)
+	}
+	return hashed
+})
+
+// AuthService 认证服务
+// 学习要点：登录校验、令牌签发与刷新令牌吊销
+type AuthService struct {
+	userDAO      dao.UserDAO
+	cache        cache.Cache
+	tokenManager *auth.TokenManager
+	loginGuard   *LoginGuard
+}
+
+// NewAuthService 创建认证服务实例
+func NewAuthService(db *gorm.DB, cache cache.Cache, tokenManager *auth.TokenManager, cfg config.AuthConfig) *AuthService {
+	return &AuthService{
+		userDAO:      dao.NewUserDAO(db),
+		cache:        cache,
+		tokenManager: tokenManager,
+		loginGuard:   NewLoginGuard(cache, cfg),
+	}
+}
+
+// Login 用户登录
+// 学习要点：不区分"用户不存在"和"密码错误"，避免泄露账号是否存在；
+// 失败次数按用户名和IP分别计数，超过阈值后临时锁定
+func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest, clientIP string) (*auth.TokenPair, *models.User, error) {
+	// 锁定期间直接拒绝，不再校验密码
+	if err := s.loginGuard.Check(ctx, req.Username, clientIP); err != nil {
+		return nil, nil, err
+	}
+
+	// 数据库故障不是凭证错误，直接返回，不计入失败次数
+	user, err := s.userDAO.GetByUsername(ctx, req.Username)
+	if err != nil && !errors.Is(err, dao.ErrUserNotFound) {
+		return nil, nil, err
+	}
+	hashed := dummyPasswordHash()
+	if user != nil {
+		hashed = user.Password
+	}
+	if !utils.CheckPassword(hashed, req.Password) || user == nil {
+		if lockErr := s.loginGuard.RecordFailure(ctx, req.Username, clientIP); lockErr != nil {
+			return nil, nil, lockErr
+		}
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	if user.Status != 1 {
+		return nil, nil, ErrUserDisabled
+	}
+
+	tokens, err := s.issueTokens(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 登录成功：清除失败记录，记录最后登录时间
+	s.loginGuard.Reset(ctx, req.Username)
+	now := time.Now()
+	user.LastLoginAt = &now
+	if err := s.userDAO.UpdateLastLogin(ctx, user.ID, now); err != nil {
+		slog.WarnContext(ctx, "更新最后登录时间失败", "error", err)
+	}
+	if err := s.cache.Delete(ctx, cache.BuildCacheKey(cache.UserCachePrefix, user.ID)); err != nil {
+		slog.WarnContext(ctx, "删除用户缓存失败", "error", err)
+	}
+
+	return tokens, user, nil
+}
+
+// RefreshToken 使用刷新令牌换取新的令牌对
+// 学习要点：刷新令牌轮换（rotation），旧令牌使用一次后立即作废
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*auth.TokenPair, error) {
+	claims, err := s.tokenManager.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// 检查用户是否被强制下线（强制下线之前签发的令牌全部作废）
+	revoked, err := s.IsTokenRevoked(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+	
+	// 检查刷新令牌是否已被吊销
+	key := cache.BuildCacheKey(cache.RefreshTokenPrefix, claims.ID)
+	exists, err := s.cache.Exists(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("校验刷新令牌失败: %w", err)
+	}
+	if !exists {
+		return nil, ErrTokenRevoked
+	}
+
+	// 作废旧的刷新令牌
+	if err := s.cache.Delete(ctx, key); err != nil {
+		return nil, fmt.Errorf("吊销刷新令牌失败: %w", err)
+	}
+
+	// 重新确认用户状态（用户可能在此期间被禁用或删除）
+	user, err := s.userDAO.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status != 1 {
+		return nil, ErrUserDisabled
+	}
+
+	return s.issueTokens(ctx, user)
+}
+
+// Logout 退出登录，吊销刷新令牌
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	claims, err := s.tokenManager.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return err
+	}
+
+	key := cache.BuildCacheKey(cache.RefreshTokenPrefix, claims.ID)
+	if err := s.cache.Delete(ctx, key); err != nil {
+		return fmt.Errorf("吊销刷新令牌失败: %w", err)
+	}
+	return nil
+}
+
+// RevokeUserTokens 强制下线：吊销用户在此之前签发的所有访问令牌和刷新令牌
+// 学习要点：记录吊销时间点而不是逐个删除令牌，签发时间早于该时间点的令牌一律拒绝；
+// 认证中间件每次请求都会检查，禁用或修改角色后旧的访问令牌立即失效。
+// 时间点精确到纳秒，强制下线后同一秒内重新登录得到的令牌不会被误判为已吊销
+func (s *AuthService) RevokeUserTokens(ctx context.Context, userID uint) error {
+	key := cache.BuildCacheKey(cache.TokenRevokedPrefix, userID)
+	if err := s.cache.Set(ctx, key, time.Now().UnixNano(), s.tokenManager.RefreshTTL()); err != nil {
+		return fmt.Errorf("强制下线失败: %w", err)
+	}
+	return nil
+}
+
+// IsTokenRevoked 判断令牌是否签发于用户被强制下线之前，访问令牌和刷新令牌共用
+func (s *AuthService) IsTokenRevoked(ctx context.Context, claims *auth.Claims) (bool, error) {
+	key := cache.BuildCacheKey(cache.TokenRevokedPrefix, claims.UserID)
+	exists, err := s.cache.Exists(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("校验令牌吊销状态失败: %w", err)
+	}
+	if !exists {
+		return false, nil
+	}
+	
+	var revokedAt int64
+	if err := s.cache.Get(ctx, key, &revokedAt); err != nil {
+		return false, fmt.Errorf("校验令牌吊销状态失败: %w", err)
+	}
+	return claims.IssuedAtNano <= revokedAt, nil
+}
+
+// issueTokens 签发令牌对并登记刷新令牌
+func (s *AuthService) issueTokens(ctx context.Context, user *models.User) (*auth.TokenPair, error) {
+	tokens, err := s.tokenManager.GenerateTokenPair(user.ID, user.Username, user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	// 在Redis中登记刷新令牌，值为用户ID，过期时间与令牌一致
+	key := cache.BuildCacheKey(cache.RefreshTokenPrefix, tokens.RefreshTokenID)
+	if err := s.cache.Set(ctx, key, user.ID, s.tokenManager.RefreshTTL()); err != nil {
+		return nil, fmt.Errorf("保存刷新令牌失败: %w", err)
+	}
+
+	return tokens, nil
+}
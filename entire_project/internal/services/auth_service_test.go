@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-management-system/internal/auth"
+	"task-management-system/internal/config"
+	"task-management-system/pkg/cache"
+)
+
+func TestAuthService_IsTokenRevoked(t *testing.T) {
+	ctx := context.Background()
+	tokenManager := auth.NewTokenManager(config.AuthConfig{Secret: "secret", Issuer: "test"})
+	s := &AuthService{cache: cache.NewMemoryCache(0), tokenManager: tokenManager}
+
+	issue := func(userID uint) *auth.Claims {
+		pair, err := tokenManager.GenerateTokenPair(userID, "alice", "member")
+		require.NoError(t, err)
+		claims, err := tokenManager.ParseAccessToken(pair.AccessToken)
+		require.NoError(t, err)
+		return claims
+	}
+
+	before := issue(1)
+	other := issue(2)
+	require.NoError(t, s.RevokeUserTokens(ctx, 1))
+	// 修改角色后立即重新登录：新令牌与吊销通常发生在同一秒内，按秒比较会被误判为已吊销
+	relogin := issue(1)
+
+	tests := []struct {
+		name   string
+		claims *auth.Claims
+		want   bool
+	}{
+		{"吊销之前签发的令牌", before, true},
+		{"同一秒内重新登录签发的令牌", relogin, false},
+		{"其他用户的令牌", other, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoked, err := s.IsTokenRevoked(ctx, tt.claims)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, revoked)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/dao"
+	"task-management-system/internal/models"
+)
+
+// 评论相关错误
+var (
+	ErrCommentNotFound  = dao.ErrCommentNotFound
+	ErrCommentForbidden = apperr.Forbidden("comment_forbidden", "只能修改或删除自己的评论")
+)
+
+// CommentService 任务评论服务
+// 学习要点：评论只能由作者编辑；删除时管理员可以删除任何评论，用于处理不当内容
+type CommentService struct {
+	commentDAO dao.CommentDAO
+	taskDAO    dao.TaskDAO
+	projectDAO dao.ProjectDAO
+}
+
+// NewCommentService 创建任务评论服务实例
+func NewCommentService(db *gorm.DB) *CommentService {
+	return &CommentService{
+		commentDAO: dao.NewCommentDAO(db),
+		taskDAO:    dao.NewTaskDAO(db),
+		projectDAO: dao.NewProjectDAO(db),
+	}
+}
+
+// CreateComment 发表评论，项目任务要求拥有该项目的任务写权限
+func (s *CommentService) CreateComment(ctx context.Context, taskID, userID uint, role string, req *models.CommentCreateRequest) (*models.TaskComment, error) {
+	if err := s.authorizeTask(ctx, taskID, userID, role, models.PermissionTaskWrite); err != nil {
+		return nil, err
+	}
+
+	comment := &models.TaskComment{TaskID: taskID, UserID: userID, Body: req.Body}
+	if err := s.commentDAO.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	// 重新加载作者信息
+	return s.commentDAO.GetByID(ctx, comment.ID)
+}
+
+// ListComments 游标分页获取任务评论（最新的在前），项目任务要求能查看该项目
+func (s *CommentService) ListComments(ctx context.Context, taskID, userID uint, role string, query models.CursorQuery) (*models.CursorPage, error) {
+	beforeID, err := decodeCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeTask(ctx, taskID, userID, role, models.PermissionTaskRead); err != nil {
+		return nil, err
+	}
+
+	limit := query.GetLimit()
+	comments, err := s.commentDAO.ListByTaskID(ctx, taskID, beforeID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := models.NewCursorPage(comments, limit, func(c models.TaskComment) uint { return c.ID })
+	return &page, nil
+}
+
+// UpdateComment 编辑评论，只有作者本人可以编辑
+func (s *CommentService) UpdateComment(ctx context.Context, taskID, commentID, userID uint, req *models.CommentUpdateRequest) (*models.TaskComment, error) {
+	comment, err := s.getTaskComment(ctx, taskID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		return nil, ErrCommentForbidden
+	}
+
+	if err := s.commentDAO.UpdateBody(ctx, commentID, req.Body); err != nil {
+		return nil, err
+	}
+	return s.commentDAO.GetByID(ctx, commentID)
+}
+
+// DeleteComment 删除评论，作者本人或管理员可以删除
+func (s *CommentService) DeleteComment(ctx context.Context, taskID, commentID, userID uint, role string) error {
+	comment, err := s.getTaskComment(ctx, taskID, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.UserID != userID && role != models.RoleAdmin {
+		return ErrCommentForbidden
+	}
+
+	return s.commentDAO.Delete(ctx, commentID)
+}
+
+// getTaskComment 获取属于指定任务的评论
+// 学习要点：评论ID和任务ID都来自URL，必须校验两者匹配，避免通过别的任务路径操作评论
+func (s *CommentService) getTaskComment(ctx context.Context, taskID, commentID uint) (*models.TaskComment, error) {
+	comment, err := s.commentDAO.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.TaskID != taskID {
+		return nil, ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// authorizeTask 校验任务存在，并且用户能以指定权限访问任务所在的项目
+func (s *CommentService) authorizeTask(ctx context.Context, taskID, userID uint, role string, permission models.Permission) error {
+	task, err := s.taskDAO.GetByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	return authorizeProjectItem(ctx, s.projectDAO, task.ProjectID, userID, role, permission)
+}
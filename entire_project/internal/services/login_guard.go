@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/config"
+	"task-management-system/pkg/cache"
+)
+
+// ErrAccountLocked 账号或IP被临时锁定
+var ErrAccountLocked = apperr.New(apperr.ErrTooManyRequests, "account_locked", "登录失败次数过多")
+
+// AccountLockedError 登录失败次数过多，账号或IP被临时锁定
+// 学习要点：自定义错误类型携带额外信息（剩余锁定时间），处理器用 errors.As 取出
+type AccountLockedError struct {
+	RetryAfter time.Duration // 剩余锁定时间
+}
+
+// Error 实现 error 接口
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("登录失败次数过多，请在%d秒后重试", int(e.RetryAfter.Seconds()+0.5))
+}
+
+// Unwrap 关联到 ErrAccountLocked，错误中间件据此返回429
+func (e *AccountLockedError) Unwrap() error {
+	return ErrAccountLocked
+}
+
+// 登录计数维度
+const (
+	loginSubjectUser = "user:" // 按用户名计数
+	loginSubjectIP   = "ip:"   // 按客户端IP计数
+)
+
+// LoginGuard 登录防爆破守卫
+// 学习要点：用 Redis 计数器统计一段时间内的失败次数，超过阈值后加锁；
+// 每次加锁时长翻倍（指数退避），登录成功后清零
+type LoginGuard struct {
+	cache          cache.Cache
+	maxUser        int64
+	maxIP          int64
+	window         time.Duration
+	baseLockout    time.Duration
+	maxLockout     time.Duration
+	backoffKeepTTL time.Duration
+}
+
+// NewLoginGuard 创建登录守卫
+func NewLoginGuard(cache cache.Cache, cfg config.AuthConfig) *LoginGuard {
+	maxLockout := cfg.GetMaxLockoutDuration()
+	return &LoginGuard{
+		cache:       cache,
+		maxUser:     cfg.GetMaxLoginAttempts(),
+		maxIP:       cfg.GetMaxLoginAttemptsPerIP(),
+		window:      cfg.GetLoginAttemptWindow(),
+		baseLockout: cfg.GetLockoutDuration(),
+		maxLockout:  maxLockout,
+		// 锁定次数在最长锁定时间的两倍内没有新的锁定才会清零
+		backoffKeepTTL: 2 * maxLockout,
+	}
+}
+
+// Check 登录前检查用户名和IP是否处于锁定状态
+// 注意：Redis 不可用时放行（fail-open），避免缓存故障导致所有人都无法登录
+func (g *LoginGuard) Check(ctx context.Context, username, ip string) error {
+	for _, subject := range g.subjects(username, ip) {
+		ttl, err := g.cache.GetTTL(ctx, cache.BuildCacheKey(cache.LoginLockPrefix, subject.key))
+		if err != nil {
+			slog.WarnContext(ctx, "检查登录锁定状态失败", "error", err)
+			continue
+		}
+		if ttl > 0 {
+			return &AccountLockedError{RetryAfter: ttl}
+		}
+	}
+	return nil
+}
+
+// RecordFailure 记录一次登录失败，达到阈值时加锁
+// 返回值不为nil表示本次失败触发了锁定
+func (g *LoginGuard) RecordFailure(ctx context.Context, username, ip string) error {
+	var locked *AccountLockedError
+	for _, subject := range g.subjects(username, ip) {
+		duration, err := g.recordFailure(ctx, subject.key, subject.limit)
+		if err != nil {
+			slog.WarnContext(ctx, "记录登录失败次数失败", "error", err)
+			continue
+		}
+		if duration > 0 && (locked == nil || duration > locked.RetryAfter) {
+			locked = &AccountLockedError{RetryAfter: duration}
+		}
+	}
+
+	if locked != nil {
+		return locked
+	}
+	return nil
+}
+
+// Reset 登录成功后清除该用户名的失败次数和退避记录
+// 学习要点：IP维度不清零，防止攻击者用自己的账号登录一次来重置计数
+func (g *LoginGuard) Reset(ctx context.Context, username string) {
+	subject := loginSubjectUser + username
+	for _, prefix := range []string{cache.LoginAttemptsPrefix, cache.LoginBackoffPrefix} {
+		if err := g.cache.Delete(ctx, cache.BuildCacheKey(prefix, subject)); err != nil {
+			slog.WarnContext(ctx, "清除登录失败记录失败", "error", err)
+		}
+	}
+}
+
+// recordFailure 对单个维度计数，达到阈值时加锁并返回锁定时长
+func (g *LoginGuard) recordFailure(ctx context.Context, subject string, limit int64) (time.Duration, error) {
+	attemptsKey := cache.BuildCacheKey(cache.LoginAttemptsPrefix, subject)
+	attempts, err := g.cache.IncrBy(ctx, attemptsKey, 1)
+	if err != nil {
+		return 0, err
+	}
+	// 第一次失败时开启统计窗口
+	if attempts == 1 {
+		if err := g.cache.SetExpire(ctx, attemptsKey, g.window); err != nil {
+			return 0, err
+		}
+	}
+	if attempts < limit {
+		return 0, nil
+	}
+
+	// 达到阈值：累加锁定次数，计算本次锁定时长
+	backoffKey := cache.BuildCacheKey(cache.LoginBackoffPrefix, subject)
+	lockCount, err := g.cache.IncrBy(ctx, backoffKey, 1)
+	if err != nil {
+		return 0, err
+	}
+	if err := g.cache.SetExpire(ctx, backoffKey, g.backoffKeepTTL); err != nil {
+		return 0, err
+	}
+
+	duration := lockoutDuration(g.baseLockout, g.maxLockout, lockCount)
+	if err := g.cache.Set(ctx, cache.BuildCacheKey(cache.LoginLockPrefix, subject), lockCount, duration); err != nil {
+		return 0, err
+	}
+
+	// 锁定后重新计数，解锁后再次达到阈值会触发更长的锁定
+	if err := g.cache.Delete(ctx, attemptsKey); err != nil {
+		return 0, err
+	}
+	return duration, nil
+}
+
+// loginSubject 计数维度及其失败次数阈值
+type loginSubject struct {
+	key   string
+	limit int64
+}
+
+// subjects 返回需要计数的维度
+func (g *LoginGuard) subjects(username, ip string) []loginSubject {
+	subjects := []loginSubject{{key: loginSubjectUser + username, limit: g.maxUser}}
+	if ip != "" {
+		subjects = append(subjects, loginSubject{key: loginSubjectIP + ip, limit: g.maxIP})
+	}
+	return subjects
+}
+
+// lockoutDuration 计算第 lockCount 次锁定的时长：base * 2^(lockCount-1)，不超过 max
+func lockoutDuration(base, max time.Duration, lockCount int64) time.Duration {
+	duration := base
+	for i := int64(1); i < lockCount && duration < max; i++ {
+		duration *= 2
+	}
+	if duration > max {
+		return max
+	}
+	return duration
+}
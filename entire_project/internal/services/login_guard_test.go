@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-management-system/internal/config"
+	"task-management-system/pkg/cache"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	tests := []struct {
+		name      string
+		lockCount int64
+		want      time.Duration
+	}{
+		{"第一次锁定使用基础时长", 1, time.Minute},
+		{"第二次锁定翻倍", 2, 2 * time.Minute},
+		{"第三次锁定再翻倍", 3, 4 * time.Minute},
+		{"超过上限时取上限", 10, 10 * time.Minute},
+		{"次数很大时不会溢出", 100, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, lockoutDuration(time.Minute, 10*time.Minute, tt.lockCount))
+		})
+	}
+}
+
+func TestLoginGuard_LocksAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	guard := NewLoginGuard(cache.NewMemoryCache(0), config.AuthConfig{
+		MaxLoginAttempts:      3,
+		MaxLoginAttemptsPerIP: 100,
+		LockoutDuration:       60,
+		MaxLockoutDuration:    600,
+	})
+
+	// 前两次失败不锁定
+	for i := 0; i < 2; i++ {
+		require.NoError(t, guard.RecordFailure(ctx, "alice", "10.0.0.1"))
+		require.NoError(t, guard.Check(ctx, "alice", "10.0.0.1"))
+	}
+
+	// 第三次失败触发锁定
+	var lockedErr *AccountLockedError
+	require.ErrorAs(t, guard.RecordFailure(ctx, "alice", "10.0.0.1"), &lockedErr)
+	assert.Equal(t, time.Minute, lockedErr.RetryAfter)
+
+	// 锁定期间换IP也无法登录，其他用户不受影响
+	assert.ErrorAs(t, guard.Check(ctx, "alice", "10.0.0.2"), &lockedErr)
+	assert.NoError(t, guard.Check(ctx, "bob", "10.0.0.2"))
+}
+
+func TestLoginGuard_IPLimit(t *testing.T) {
+	ctx := context.Background()
+	guard := NewLoginGuard(cache.NewMemoryCache(0), config.AuthConfig{
+		MaxLoginAttempts:      100,
+		MaxLoginAttemptsPerIP: 2,
+	})
+
+	// 同一IP尝试不同用户名，达到IP阈值后锁定该IP
+	require.NoError(t, guard.RecordFailure(ctx, "alice", "10.0.0.1"))
+	var lockedErr *AccountLockedError
+	require.ErrorAs(t, guard.RecordFailure(ctx, "bob", "10.0.0.1"), &lockedErr)
+
+	assert.ErrorAs(t, guard.Check(ctx, "carol", "10.0.0.1"), &lockedErr)
+	assert.NoError(t, guard.Check(ctx, "carol", "10.0.0.2"))
+}
+
+func TestLoginGuard_ResetOnSuccess(t *testing.T) {
+	ctx := context.Background()
+	guard := NewLoginGuard(cache.NewMemoryCache(0), config.AuthConfig{MaxLoginAttempts: 2})
+
+	require.NoError(t, guard.RecordFailure(ctx, "alice", ""))
+	guard.Reset(ctx, "alice")
+
+	// 清零后需要重新累计到阈值才会锁定
+	require.NoError(t, guard.RecordFailure(ctx, "alice", ""))
+	assert.NoError(t, guard.Check(ctx, "alice", ""))
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/dao"
+	"task-management-system/internal/models"
+)
+
+// 项目相关错误
+var (
+	ErrProjectNotFound      = dao.ErrProjectNotFound
+	ErrProjectForbidden     = apperr.Forbidden("project_forbidden", "没有权限访问此项目")
+	ErrProjectNotEmpty      = apperr.Conflict("project_not_empty", "项目中还有任务，不能删除")
+	ErrProjectMemberExists  = apperr.Conflict("project_member_exists", "用户已经是项目成员")
+	ErrProjectOwnerReadonly = apperr.Validation("project_owner_readonly", "不能修改或移除项目所有者")
+)
+
+// ProjectService 项目服务
+// 学习要点：项目内的操作按项目角色授权，全局管理员可以管理所有项目
+type ProjectService struct {
+	projectDAO dao.ProjectDAO
+	userDAO    dao.UserDAO
+	tagDAO     dao.TagDAO
+	db         *gorm.DB
+}
+
+// NewProjectService 创建项目服务实例
+func NewProjectService(db *gorm.DB) *ProjectService {
+	return &ProjectService{
+		projectDAO: dao.NewProjectDAO(db),
+		userDAO:    dao.NewUserDAO(db),
+		tagDAO:     dao.NewTagDAO(db),
+		db:         db,
+	}
+}
+
+// Authorize 校验用户在项目中是否拥有权限
+// 学习要点：先确认项目存在再判断权限，不存在时返回 404，存在但不是成员时返回 403
+func (s *ProjectService) Authorize(ctx context.Context, projectID, userID uint, role string, permission models.Permission) error {
+	if _, err := s.projectDAO.GetByID(ctx, projectID); err != nil {
+		return err
+	}
+	if role == models.RoleAdmin {
+		return nil
+	}
+	allowed, err := hasProjectPermission(ctx, s.projectDAO, projectID, userID, permission)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrProjectForbidden
+	}
+	return nil
+}
+
+// CreateProject 创建项目，创建者成为项目所有者
+func (s *ProjectService) CreateProject(ctx context.Context, userID uint, req *models.ProjectCreateRequest) (*models.Project, error) {
+	project := &models.Project{Name: req.Name, Description: req.Description, OwnerID: userID}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		projectDAO := s.projectDAO.WithTx(tx)
+		if err := projectDAO.Create(ctx, project); err != nil {
+			return err
+		}
+		return projectDAO.AddMember(ctx, &models.ProjectMember{
+			ProjectID: project.ID,
+			UserID:    userID,
+			Role:      models.ProjectRoleOwner,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetProject(ctx, project.ID, userID, "")
+}
+
+// GetProject 获取项目详情（包含成员列表）
+func (s *ProjectService) GetProject(ctx context.Context, id, userID uint, role string) (*models.Project, error) {
+	if err := s.Authorize(ctx, id, userID, role, models.PermissionTaskRead); err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectDAO.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	project.Members, err = s.projectDAO.ListMembers(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// ListProjects 获取项目列表：管理员看到所有项目，其他用户只看到自己参与的项目
+func (s *ProjectService) ListProjects(ctx context.Context, userID uint, role string, page, pageSize int) (*models.PageResult, error) {
+	offset := (page - 1) * pageSize
+
+	var projects []models.Project
+	var total int64
+	var err error
+	if role == models.RoleAdmin {
+		projects, total, err = s.projectDAO.List(ctx, offset, pageSize)
+	} else {
+		projects, total, err = s.projectDAO.ListByMember(ctx, userID, offset, pageSize)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PageResult{
+		List: projects,
+		PageInfo: models.PageInfo{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+// UpdateProject 更新项目信息
+func (s *ProjectService) UpdateProject(ctx context.Context, id, userID uint, role string, req *models.ProjectUpdateRequest) (*models.Project, error) {
+	if err := s.Authorize(ctx, id, userID, role, models.PermissionProjectManage); err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if err := s.projectDAO.UpdateFields(ctx, id, updates); err != nil {
+		return nil, err
+	}
+
+	return s.GetProject(ctx, id, userID, role)
+}
+
+// DeleteProject 删除项目
+// 学习要点：项目中还有任务时拒绝删除，避免任务失去归属；项目标签和成员关系随项目一起删除
+func (s *ProjectService) DeleteProject(ctx context.Context, id, userID uint, role string) error {
+	if err := s.Authorize(ctx, id, userID, role, models.PermissionProjectDelete); err != nil {
+		return err
+	}
+
+	count, err := s.projectDAO.CountTasks(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrProjectNotEmpty
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		projectDAO := s.projectDAO.WithTx(tx)
+		if err := s.tagDAO.WithTx(tx).DeleteByProjectID(ctx, id); err != nil {
+			return err
+		}
+		if err := projectDAO.RemoveAllMembers(ctx, id); err != nil {
+			return err
+		}
+		return projectDAO.Delete(ctx, id)
+	})
+}
+
+// ListMembers 获取项目成员列表
+func (s *ProjectService) ListMembers(ctx context.Context, id, userID uint, role string) ([]models.ProjectMember, error) {
+	if err := s.Authorize(ctx, id, userID, role, models.PermissionTaskRead); err != nil {
+		return nil, err
+	}
+	return s.projectDAO.ListMembers(ctx, id)
+}
+
+// AddMember 添加项目成员
+func (s *ProjectService) AddMember(ctx context.Context, id, userID uint, role string, req *models.ProjectMemberRequest) (*models.ProjectMember, error) {
+	if err := s.Authorize(ctx, id, userID, role, models.PermissionProjectManage); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userDAO.GetByID(ctx, req.UserID); err != nil {
+		return nil, err
+	}
+	if _, err := s.projectDAO.GetMember(ctx, id, req.UserID); err == nil {
+		return nil, ErrProjectMemberExists
+	} else if !errors.Is(err, dao.ErrProjectMemberNotFound) {
+		return nil, err
+	}
+
+	member := &models.ProjectMember{ProjectID: id, UserID: req.UserID, Role: req.Role}
+	if err := s.projectDAO.AddMember(ctx, member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// UpdateMemberRole 修改项目成员角色，所有者的角色不能修改
+func (s *ProjectService) UpdateMemberRole(ctx context.Context, id, userID uint, role string, memberID uint, req *models.ProjectMemberUpdateRequest) error {
+	if err := s.Authorize(ctx, id, userID, role, models.PermissionProjectManage); err != nil {
+		return err
+	}
+
+	member, err := s.projectDAO.GetMember(ctx, id, memberID)
+	if err != nil {
+		return err
+	}
+	if member.Role == models.ProjectRoleOwner {
+		return ErrProjectOwnerReadonly
+	}
+
+	return s.projectDAO.UpdateMemberRole(ctx, id, memberID, req.Role)
+}
+
+// RemoveMember 移除项目成员
+// 学习要点：成员可以自己退出项目；移除其他成员需要管理权限；所有者不能被移除
+func (s *ProjectService) RemoveMember(ctx context.Context, id, userID uint, role string, memberID uint) error {
+	if memberID != userID {
+		if err := s.Authorize(ctx, id, userID, role, models.PermissionProjectManage); err != nil {
+			return err
+		}
+	}
+
+	member, err := s.projectDAO.GetMember(ctx, id, memberID)
+	if err != nil {
+		return err
+	}
+	if member.Role == models.ProjectRoleOwner {
+		return ErrProjectOwnerReadonly
+	}
+
+	return s.projectDAO.RemoveMember(ctx, id, memberID)
+}
+
+// hasProjectPermission 判断用户的项目角色是否拥有权限，不是项目成员时返回 false
+// 学习要点：TaskService 和 ProjectService 共用同一套判断，保证任务和项目的授权规则一致
+func hasProjectPermission(ctx context.Context, projectDAO dao.ProjectDAO, projectID, userID uint, permission models.Permission) (bool, error) {
+	if userID == 0 {
+		return false, nil
+	}
+	member, err := projectDAO.GetMember(ctx, projectID, userID)
+	if err != nil {
+		if errors.Is(err, dao.ErrProjectMemberNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return models.ProjectRoleHasPermission(member.Role, permission), nil
+}
+
+// authorizeProjectItem 校验用户能否访问属于项目的数据（任务动态、评论、项目标签等）
+// 学习要点：projectID 为空表示全局数据，不需要项目权限；系统管理员可以访问所有项目
+func authorizeProjectItem(ctx context.Context, projectDAO dao.ProjectDAO, projectID *uint, userID uint, role string, permission models.Permission) error {
+	if projectID == nil || role == models.RoleAdmin {
+		return nil
+	}
+	allowed, err := hasProjectPermission(ctx, projectDAO, *projectID, userID, permission)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrProjectForbidden
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"gorm.io/gorm"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/dao"
+	"task-management-system/internal/models"
+)
+
+// 标签相关错误
+var (
+	ErrTagNotFound     = dao.ErrTagNotFound
+	ErrTagNameExists   = dao.ErrTagNameExists
+	ErrInvalidTagColor = apperr.Validation("invalid_tag_color", "标签颜色格式错误，应为 #RRGGBB")
+)
+
+// defaultTagColor 未指定颜色时使用的默认颜色
+const defaultTagColor = "#9E9E9E"
+
+// tagColorPattern 标签颜色格式：#RRGGBB
+var tagColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// TagService 标签服务
+// 学习要点：基于DAO的服务层，唯一性校验，安全删除
+type TagService struct {
+	tagDAO     dao.TagDAO
+	projectDAO dao.ProjectDAO
+	db         *gorm.DB
+}
+
+// NewTagService 创建标签服务实例
+func NewTagService(db *gorm.DB) *TagService {
+	return &TagService{
+		tagDAO:     dao.NewTagDAO(db),
+		projectDAO: dao.NewProjectDAO(db),
+		db:         db,
+	}
+}
+
+// CreateTag 创建标签，projectID 为 nil 时创建全局标签
+func (s *TagService) CreateTag(ctx context.Context, projectID *uint, req *models.TagCreateRequest) (*models.TagResponse, error) {
+	color := req.Color
+	if color == "" {
+		color = defaultTagColor
+	}
+	if !tagColorPattern.MatchString(color) {
+		return nil, ErrInvalidTagColor
+	}
+
+	if err := s.ensureNameAvailable(ctx, projectID, req.Name, 0); err != nil {
+		return nil, err
+	}
+
+	tag := &models.Tag{Name: req.Name, Color: color, ProjectID: projectID}
+	if err := s.tagDAO.Create(ctx, tag); err != nil {
+		return nil, err
+	}
+
+	resp := tag.ToResponse(0)
+	return &resp, nil
+}
+
+// GetTag 获取标签详情（包含使用次数），项目标签要求能查看该项目
+func (s *TagService) GetTag(ctx context.Context, id, userID uint, role string) (*models.TagResponse, error) {
+	tag, err := s.tagDAO.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeProjectItem(ctx, s.projectDAO, tag.ProjectID, userID, role, models.PermissionTaskRead); err != nil {
+		return nil, err
+	}
+
+	return s.tagResponse(ctx, tag)
+}
+
+// tagResponse 统计标签使用次数并转换为响应
+func (s *TagService) tagResponse(ctx context.Context, tag *models.Tag) (*models.TagResponse, error) {
+	usage, err := s.tagDAO.CountUsage(ctx, []uint{tag.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := tag.ToResponse(usage[tag.ID])
+	return &resp, nil
+}
+
+// GetTagList 获取标签列表（包含每个标签的使用次数），projectID 为 nil 时返回全局标签
+// 学习要点：一次聚合查询拿到整页标签的使用次数，避免N+1查询
+func (s *TagService) GetTagList(ctx context.Context, projectID *uint, page, pageSize int) (*models.PageResult, error) {
+	offset := (page - 1) * pageSize
+	tags, total, err := s.tagDAO.List(ctx, projectID, offset, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(tags))
+	for i, tag := range tags {
+		ids[i] = tag.ID
+	}
+	usage, err := s.tagDAO.CountUsage(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]models.TagResponse, len(tags))
+	for i := range tags {
+		list[i] = tags[i].ToResponse(usage[tags[i].ID])
+	}
+
+	return &models.PageResult{
+		List: list,
+		PageInfo: models.PageInfo{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+// UpdateTag 更新标签
+func (s *TagService) UpdateTag(ctx context.Context, id uint, req *models.TagUpdateRequest) (*models.TagResponse, error) {
+	tag, err := s.tagDAO.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil && *req.Name != tag.Name {
+		if err := s.ensureNameAvailable(ctx, tag.ProjectID, *req.Name, id); err != nil {
+			return nil, err
+		}
+		tag.Name = *req.Name
+	}
+	if req.Color != nil {
+		if !tagColorPattern.MatchString(*req.Color) {
+			return nil, ErrInvalidTagColor
+		}
+		tag.Color = *req.Color
+	}
+
+	if err := s.tagDAO.Update(ctx, tag); err != nil {
+		return nil, err
+	}
+
+	return s.tagResponse(ctx, tag)
+}
+
+// DeleteTag 安全删除标签
+// 学习要点：先解除 task_tags 中的关联再删除标签，两步放在同一个事务中
+func (s *TagService) DeleteTag(ctx context.Context, id uint) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		tagDAO := s.tagDAO.WithTx(tx)
+
+		if _, err := tagDAO.GetByID(ctx, id); err != nil {
+			return err
+		}
+
+		if err := tagDAO.DetachFromTasks(ctx, id); err != nil {
+			return err
+		}
+
+		return tagDAO.Delete(ctx, id)
+	})
+}
+
+// DeleteProjectTag 删除项目标签，标签必须属于该项目
+func (s *TagService) DeleteProjectTag(ctx context.Context, projectID, id uint) error {
+	tag, err := s.tagDAO.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if tag.ProjectID == nil || *tag.ProjectID != projectID {
+		return fmt.Errorf("%w: ID=%d", ErrTagNotFound, id)
+	}
+	return s.DeleteTag(ctx, id)
+}
+
+// ensureNameAvailable 检查标签名称是否已被同一范围（全局或同一项目）内的其他标签占用
+func (s *TagService) ensureNameAvailable(ctx context.Context, projectID *uint, name string, excludeID uint) error {
+	existing, err := s.tagDAO.GetByName(ctx, projectID, name)
+	if err != nil {
+		if errors.Is(err, dao.ErrTagNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != excludeID {
+		return fmt.Errorf("%w: %s", ErrTagNameExists, name)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"task-management-system/internal/models"
+)
+
+// activityFields 记录动态的任务字段，按固定顺序生成动态，保证同一次修改的记录顺序稳定
+var activityFields = []string{"title", "description", "status", "priority", "start_time", "end_time", "due_date"}
+
+// buildTaskActivities 对比修改前的任务和本次更新的字段，生成任务动态
+// 学习要点：只记录真正发生变化的字段；tagIDs 为 nil 表示本次没有修改标签；
+// 状态变化按状态流转记录动作（如 started、reopened），其他字段使用 action
+func buildTaskActivities(old *models.Task, updates map[string]interface{}, tagIDs []uint, userID uint, action string) []models.TaskActivity {
+	var activities []models.TaskActivity
+	add := func(action, field, oldValue, newValue string) {
+		if oldValue == newValue {
+			return
+		}
+		activities = append(activities, models.TaskActivity{
+			TaskID:   old.ID,
+			UserID:   userID,
+			Action:   action,
+			Field:    field,
+			OldValue: oldValue,
+			NewValue: newValue,
+		})
+	}
+
+	for _, field := range activityFields {
+		value, ok := updates[field]
+		if !ok {
+			continue
+		}
+		fieldAction := action
+		if status, ok := value.(int); ok && field == "status" {
+			if transition, ok := models.FindTaskTransition(old.Status, status); ok {
+				fieldAction = transition.Action
+			}
+		}
+		add(fieldAction, field, taskFieldValue(old, field), updateValue(field, value))
+	}
+
+	if tagIDs != nil {
+		oldTagIDs := make([]uint, 0, len(old.Tags))
+		for _, tag := range old.Tags {
+			oldTagIDs = append(oldTagIDs, tag.ID)
+		}
+		add(action, "tags", formatIDs(oldTagIDs), formatIDs(tagIDs))
+	}
+
+	return activities
+}
+
+// buildAssigneeActivities 生成负责人变化的任务动态，assigneeIDs 为修改后的全部负责人，没有变化时返回 nil
+func buildAssigneeActivities(task *models.Task, assigneeIDs []uint, userID uint) []models.TaskActivity {
+	return buildIDListActivities(task.ID, "assignees", userIDsOf(task.Assignees), assigneeIDs, userID)
+}
+
+// buildDependencyActivities 生成前置任务变化的任务动态，blockerIDs 为修改后的全部前置任务，没有变化时返回 nil
+func buildDependencyActivities(task *models.Task, blockerIDs []uint, userID uint) []models.TaskActivity {
+	return buildIDListActivities(task.ID, "blocked_by", taskIDsOf(task.BlockedBy), blockerIDs, userID)
+}
+
+// buildIDListActivities 生成ID列表字段变化的任务动态，没有变化时返回 nil
+func buildIDListActivities(taskID uint, field string, oldIDs, newIDs []uint, userID uint) []models.TaskActivity {
+	oldValue, newValue := formatIDs(oldIDs), formatIDs(newIDs)
+	if oldValue == newValue {
+		return nil
+	}
+	return []models.TaskActivity{{
+		TaskID:   taskID,
+		UserID:   userID,
+		Action:   models.ActivityActionUpdated,
+		Field:    field,
+		OldValue: oldValue,
+		NewValue: newValue,
+	}}
+}
+
+// userIDsOf 提取用户ID列表
+func userIDsOf(users []models.User) []uint {
+	ids := make([]uint, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
+
+// taskIDsOf 提取任务ID列表
+func taskIDsOf(tasks []models.Task) []uint {
+	ids := make([]uint, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+// taskFieldValue 获取任务字段当前值的字符串形式
+func taskFieldValue(task *models.Task, field string) string {
+	switch field {
+	case "title":
+		return task.Title
+	case "description":
+		return task.Description
+	case "status":
+		return models.StatusKey(task.Status)
+	case "priority":
+		return models.PriorityKey(task.Priority)
+	case "start_time":
+		return activityValue(task.StartTime)
+	case "end_time":
+		return activityValue(task.EndTime)
+	case "due_date":
+		return activityValue(task.DueDate)
+	default:
+		return ""
+	}
+}
+
+// updateValue 把 updates 中的新值转换为动态中保存的字符串
+// 学习要点：状态和优先级保存英文标识而不是数字，前端可以直接展示
+func updateValue(field string, value interface{}) string {
+	switch field {
+	case "status":
+		return models.StatusKey(value.(int))
+	case "priority":
+		return models.PriorityKey(value.(int))
+	default:
+		return activityValue(value)
+	}
+}
+
+// activityValue 把字符串或时间转换为动态中保存的字符串，时间统一为 RFC3339
+func activityValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	default:
+		return ""
+	}
+}
+
+// uniqueIDs 返回去重并排序后的ID列表
+func uniqueIDs(ids []uint) []uint {
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	return slices.Compact(sorted)
+}
+
+// formatIDs 把ID列表格式化为去重排序后以逗号分隔的字符串，保证顺序不同的同一组ID不被记为变化
+func formatIDs(ids []uint) string {
+	sorted := uniqueIDs(ids)
+	parts := make([]string, 0, len(sorted))
+	for _, id := range sorted {
+		parts = append(parts, strconv.FormatUint(uint64(id), 10))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/models"
+)
+
+func TestBuildTaskActivities(t *testing.T) {
+	due := time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC)
+	old := &models.Task{
+		BaseModel:   models.BaseModel{ID: 7},
+		Title:       "写周报",
+		Description: "本周进展",
+		Status:      models.TaskStatusPending,
+		Priority:    models.TaskPriorityMedium,
+		DueDate:     &due,
+		Tags:        []models.Tag{{BaseModel: models.BaseModel{ID: 3}}, {BaseModel: models.BaseModel{ID: 1}}},
+	}
+
+	type change struct{ field, oldValue, newValue string }
+	tests := []struct {
+		name    string
+		updates map[string]interface{}
+		tagIDs  []uint
+		want    []change
+	}{
+		{"没有修改", map[string]interface{}{}, nil, nil},
+		{"值未变化的字段不记录", map[string]interface{}{"title": "写周报", "priority": models.TaskPriorityMedium}, nil, nil},
+		{"状态和优先级记录英文标识", map[string]interface{}{
+			"priority": models.TaskPriorityUrgent,
+			"status":   models.TaskStatusInProgress,
+		}, nil, []change{
+			{"status", "pending", "in_progress"},
+			{"priority", "medium", "urgent"},
+		}},
+		{"时间字段", map[string]interface{}{"due_date": (*time.Time)(nil)}, nil, []change{
+			{"due_date", "2024-05-01T18:00:00Z", ""},
+		}},
+		{"标签顺序不同视为未变化", map[string]interface{}{}, []uint{1, 3, 3}, nil},
+		{"标签变化", map[string]interface{}{}, []uint{}, []change{{"tags", "1,3", ""}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activities := buildTaskActivities(old, tt.updates, tt.tagIDs, 9, models.ActivityActionUpdated)
+
+			var got []change
+			for _, activity := range activities {
+				assert.Equal(t, uint(7), activity.TaskID)
+				assert.Equal(t, uint(9), activity.UserID)
+				wantAction := models.ActivityActionUpdated
+				if activity.Field == "status" {
+					wantAction = models.ActivityActionStarted // 状态变化按流转记录
+				}
+				assert.Equal(t, wantAction, activity.Action)
+				got = append(got, change{activity.Field, activity.OldValue, activity.NewValue})
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCanEditTask(t *testing.T) {
+	task := &models.Task{
+		UserID:    1,
+		Assignees: []models.User{{BaseModel: models.BaseModel{ID: 2}}},
+		Watchers:  []models.User{{BaseModel: models.BaseModel{ID: 3}}},
+	}
+
+	assert.True(t, canEditTask(task, 1), "创建者可以修改")
+	assert.True(t, canEditTask(task, 2), "负责人可以修改")
+	assert.False(t, canEditTask(task, 3), "关注者只能查看")
+	assert.False(t, canEditTask(task, 4))
+}
+
+func TestBuildAssigneeActivities(t *testing.T) {
+	task := &models.Task{
+		BaseModel: models.BaseModel{ID: 7},
+		Assignees: []models.User{{BaseModel: models.BaseModel{ID: 2}}},
+	}
+
+	activities := buildAssigneeActivities(task, []uint{5, 2, 5}, 1)
+	if assert.Len(t, activities, 1) {
+		assert.Equal(t, "assignees", activities[0].Field)
+		assert.Equal(t, "2", activities[0].OldValue)
+		assert.Equal(t, "2,5", activities[0].NewValue)
+	}
+
+	assert.Nil(t, buildAssigneeActivities(task, []uint{2}, 1), "负责人没有变化时不记录")
+}
+
+func TestDecodeCursor(t *testing.T) {
+	id, err := decodeCursor(models.EncodeCursor(42))
+	assert.NoError(t, err)
+	assert.Equal(t, uint(42), id)
+
+	_, err = decodeCursor("not-a-cursor")
+	assert.ErrorIs(t, err, models.ErrInvalidCursor)
+	assert.ErrorIs(t, err, apperr.ErrValidation)
+	assert.Equal(t, "invalid_cursor", apperr.Code(err))
+}
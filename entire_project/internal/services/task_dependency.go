@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/models"
+)
+
+// blockerLoader 批量获取任务的前置任务ID，键为任务ID
+type blockerLoader func(ctx context.Context, taskIDs []uint) (map[uint][]uint, error)
+
+// findDependencyCycle 判断让 taskID 依赖 blockerID 是否会形成环，形成环时返回环上的任务ID，
+// 从 taskID 开始、到 taskID 结束，相邻两项表示前者依赖后者
+// 学习要点：新增边 task→blocker 形成环，当且仅当从 blocker 沿已有的依赖能走到 task；
+// 按层广度优先遍历，每层只查询一次数据库，同时记录每个节点是从哪里走到的，用于还原路径
+func findDependencyCycle(ctx context.Context, taskID, blockerID uint, loadBlockers blockerLoader) ([]uint, error) {
+	if taskID == blockerID {
+		return []uint{taskID, taskID}, nil
+	}
+
+	cameFrom := map[uint]uint{blockerID: 0}
+	frontier := []uint{blockerID}
+	for len(frontier) > 0 {
+		blockers, err := loadBlockers(ctx, frontier)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []uint
+		for _, id := range frontier {
+			for _, blocker := range blockers[id] {
+				if _, seen := cameFrom[blocker]; seen {
+					continue
+				}
+				cameFrom[blocker] = id
+				if blocker == taskID {
+					return dependencyPath(cameFrom, taskID), nil
+				}
+				next = append(next, blocker)
+			}
+		}
+		frontier = next
+	}
+	return nil, nil
+}
+
+// dependencyPath 根据遍历记录还原环：taskID → blockerID → ... → taskID
+func dependencyPath(cameFrom map[uint]uint, taskID uint) []uint {
+	path := []uint{taskID}
+	for id := cameFrom[taskID]; id != 0; id = cameFrom[id] {
+		path = append(path, id)
+	}
+	path = append(path, taskID)
+	slices.Reverse(path)
+	return path
+}
+
+// dependencyCycleError 形成环时的校验错误，错误信息中给出环上的任务，方便用户找到需要移除的依赖
+func dependencyCycleError(cycle []uint) error {
+	parts := make([]string, 0, len(cycle))
+	for _, id := range cycle {
+		parts = append(parts, strconv.FormatUint(uint64(id), 10))
+	}
+	return apperr.Validation("dependency_cycle",
+		fmt.Sprintf("添加依赖会形成循环依赖：%s（A → B 表示任务A依赖任务B）", strings.Join(parts, " → ")))
+}
+
+// openBlockerIDs 返回尚未结束的前置任务ID，task 需要预加载 BlockedBy
+func openBlockerIDs(task *models.Task) []uint {
+	var ids []uint
+	for _, blocker := range task.BlockedBy {
+		if !blocker.IsClosed() {
+			ids = append(ids, blocker.ID)
+		}
+	}
+	return ids
+}
+
+// taskBlockedError 前置任务没有结束时开始或完成任务的冲突错误
+func taskBlockedError(blockerIDs []uint) error {
+	return apperr.Conflict("task_blocked", fmt.Sprintf("前置任务 %s 还没有结束，不能开始或完成此任务", formatIDs(blockerIDs)))
+}
+
+// sameProject 判断两个任务是否属于同一项目（都不属于项目也算同一项目）
+func sameProject(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// blocksStart 判断状态变更是否需要前置任务全部结束：开始或完成任务时需要，其他变更不受影响
+func blocksStart(oldStatus, newStatus int) bool {
+	if oldStatus == newStatus {
+		return false
+	}
+	return newStatus == models.TaskStatusInProgress || newStatus == models.TaskStatusCompleted
+}
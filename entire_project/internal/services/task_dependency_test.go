@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/models"
+)
+
+func TestFindDependencyCycle(t *testing.T) {
+	// 已有依赖：2 依赖 1，3 依赖 2，4 依赖 2 和 3，6 依赖 5
+	graph := map[uint][]uint{2: {1}, 3: {2}, 4: {2, 3}, 6: {5}}
+	var queries int
+	load := func(_ context.Context, ids []uint) (map[uint][]uint, error) {
+		queries++
+		result := make(map[uint][]uint)
+		for _, id := range ids {
+			result[id] = graph[id]
+		}
+		return result, nil
+	}
+
+	tests := []struct {
+		name            string
+		taskID, blocker uint
+		want            []uint
+	}{
+		{"依赖自己", 1, 1, []uint{1, 1}},
+		{"直接环", 1, 2, []uint{1, 2, 1}},
+		{"间接环", 1, 4, []uint{1, 4, 2, 1}},
+		{"已有的传递依赖可以再直接添加", 4, 1, nil},
+		{"不相连的任务", 5, 3, nil},
+		{"菱形依赖不是环", 6, 4, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cycle, err := findDependencyCycle(context.Background(), tt.taskID, tt.blocker, load)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cycle)
+		})
+	}
+
+	queries = 0
+	_, err := findDependencyCycle(context.Background(), 9, 4, load)
+	require.NoError(t, err)
+	assert.Equal(t, 3, queries, "每层依赖只查询一次")
+
+	failing := func(context.Context, []uint) (map[uint][]uint, error) { return nil, errors.New("db down") }
+	_, err = findDependencyCycle(context.Background(), 1, 2, failing)
+	assert.Error(t, err)
+}
+
+func TestDependencyCycleError(t *testing.T) {
+	err := dependencyCycleError([]uint{1, 4, 2, 1})
+	assert.ErrorIs(t, err, apperr.ErrValidation)
+	assert.Contains(t, err.Error(), "1 → 4 → 2 → 1")
+}
+
+func TestBlocksStart(t *testing.T) {
+	assert.True(t, blocksStart(models.TaskStatusPending, models.TaskStatusInProgress))
+	assert.True(t, blocksStart(models.TaskStatusInProgress, models.TaskStatusCompleted))
+	assert.False(t, blocksStart(models.TaskStatusInProgress, models.TaskStatusInProgress), "状态未变化")
+	assert.False(t, blocksStart(models.TaskStatusPending, models.TaskStatusCancelled), "取消不受前置任务限制")
+
+	task := &models.Task{BlockedBy: []models.Task{
+		{BaseModel: models.BaseModel{ID: 1}, Status: models.TaskStatusCompleted},
+		{BaseModel: models.BaseModel{ID: 2}, Status: models.TaskStatusInProgress},
+		{BaseModel: models.BaseModel{ID: 3}, Status: models.TaskStatusCancelled},
+	}}
+	assert.Equal(t, []uint{2}, openBlockerIDs(task))
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-management-system/internal/dao"
+	"task-management-system/internal/models"
+)
+
+// fakeProjectDAO 内存中的项目成员，只实现权限判断用到的方法
+type fakeProjectDAO struct {
+	dao.ProjectDAO
+	roles map[uint]string // 用户ID -> 项目角色
+}
+
+func (f *fakeProjectDAO) GetMember(_ context.Context, projectID, userID uint) (*models.ProjectMember, error) {
+	role, ok := f.roles[userID]
+	if !ok {
+		return nil, dao.ErrProjectMemberNotFound
+	}
+	return &models.ProjectMember{ProjectID: projectID, UserID: userID, Role: role}, nil
+}
+
+func (f *fakeProjectDAO) RemoveMember(_ context.Context, _, userID uint) error {
+	delete(f.roles, userID)
+	return nil
+}
+
+// fakeTaskDAO 只返回固定任务的任务DAO
+type fakeTaskDAO struct {
+	dao.TaskDAO
+	task *models.Task
+}
+
+func (f *fakeTaskDAO) GetByIDWithAssociations(context.Context, uint) (*models.Task, error) {
+	return f.task, nil
+}
+
+func TestCheckEditable_ProjectTask(t *testing.T) {
+	projectID := uint(10)
+	task := &models.Task{
+		UserID:    1,
+		ProjectID: &projectID,
+		Assignees: []models.User{{BaseModel: models.BaseModel{ID: 2}}},
+	}
+	projects := &fakeProjectDAO{roles: map[uint]string{
+		1: models.ProjectRoleMember,
+		2: models.ProjectRoleViewer,
+		3: models.ProjectRoleMaintainer,
+		4: models.ProjectRoleMember,
+	}}
+	s := &TaskService{projectDAO: projects, taskDAO: &fakeTaskDAO{task: task}}
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		userID uint
+		want   error
+	}{
+		{"创建者", 1, nil},
+		{"只读角色的负责人", 2, ErrProjectForbidden},
+		{"项目维护者", 3, nil},
+		{"与任务无关的成员", 4, ErrTaskForbidden},
+		{"不是项目成员", 5, ErrProjectForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.checkEditable(ctx, task, tt.userID)
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	// 创建者被移出项目后不能再修改自己的任务，也不能通过空的更新请求读取任务
+	require.NoError(t, projects.RemoveMember(ctx, projectID, 1))
+	_, err := s.UpdateTask(ctx, 1, 1, &models.TaskUpdateRequest{})
+	assert.ErrorIs(t, err, ErrProjectForbidden)
+}
@@ -1,51 +1,118 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
 	"time"
 
 	"gorm.io/gorm"
-	"task-management-system/internal/database"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/config"
+	"task-management-system/internal/dao"
 	"task-management-system/internal/models"
-	"task-management-system/pkg/redis"
+	"task-management-system/pkg/cache"
 )
 
-// TaskService 任务服务结构体
-// 学习要点：复杂业务逻辑处理，多表关联查询，缓存策略
+// 任务相关错误
+var (
+	ErrTaskNotFound    = dao.ErrTaskNotFound
+	ErrTaskForbidden   = apperr.Forbidden("task_forbidden", "没有权限操作此任务")
+	ErrInvalidAssignee = apperr.Validation("invalid_assignee", "指派的用户不存在或不是项目成员")
+	ErrInvalidTaskTag  = apperr.Validation("invalid_task_tag", "标签不存在或不属于任务所在的项目")
+
+	ErrInvalidParentTask = apperr.Validation("invalid_parent_task", "父任务不存在或不属于同一项目")
+	ErrInvalidDependency = apperr.Validation("invalid_dependency", "前置任务不存在或不属于同一项目")
+)
+
+// invalidTransitionError 不符合状态流转表的状态变更
+func invalidTransitionError(from, to int) error {
+	return apperr.Conflict("invalid_status_transition",
+		fmt.Sprintf("任务状态不能从「%s」变更为「%s」", models.StatusText(from), models.StatusText(to)))
+}
+
+// decodeCursor 解析分页游标，格式错误（models.ErrInvalidCursor）转换为参数校验错误
+// 学习要点：models 只定义哨兵错误，映射为HTTP语义的业务错误只在服务层做一次
+func decodeCursor(cursor string) (uint, error) {
+	id, err := models.DecodeCursor(cursor)
+	if errors.Is(err, models.ErrInvalidCursor) {
+		return 0, apperr.Wrap(err, apperr.ErrValidation, "invalid_cursor", "分页参数错误")
+	}
+	return id, err
+}
+
+// TaskService 任务服务
+// 学习要点：服务层只编排业务逻辑，数据访问全部通过DAO完成；
+// 需要事务时用 WithTx 得到绑定事务的DAO
 type TaskService struct {
-	db    *gorm.DB
-	cache *redis.CacheService
+	taskDAO     dao.TaskDAO
+	userDAO     dao.UserDAO
+	activityDAO dao.ActivityDAO
+	projectDAO  dao.ProjectDAO
+	tagDAO      dao.TagDAO
+	cache       cache.Cache
+	live        *config.Live // 缓存有效期等可热更新的配置
+	db          *gorm.DB
 }
 
 // NewTaskService 创建任务服务实例
-func NewTaskService() *TaskService {
+func NewTaskService(db *gorm.DB, cache cache.Cache, live *config.Live) *TaskService {
 	return &TaskService{
-		db:    database.DB,
-		cache: redis.NewCacheService(),
+		taskDAO:     dao.NewTaskDAO(db),
+		userDAO:     dao.NewUserDAO(db),
+		activityDAO: dao.NewActivityDAO(db),
+		projectDAO:  dao.NewProjectDAO(db),
+		tagDAO:      dao.NewTagDAO(db),
+		cache:       cache,
+		live:        live,
+		db:          db,
 	}
 }
 
 // CreateTask 创建任务
-// 学习要点：关联数据处理，事务管理，多对多关系
-func (s *TaskService) CreateTask(userID uint, req *models.TaskCreateRequest) (*models.Task, error) {
+// 学习要点：任务、标签关联和创建动态在同一个事务中写入
+func (s *TaskService) CreateTask(ctx context.Context, userID uint, req *models.TaskCreateRequest) (*models.Task, error) {
 	// 验证用户是否存在
-	var user models.User
-	if err := s.db.First(&user, userID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("用户不存在: ID=%d", userID)
+	if _, err := s.userDAO.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// 在项目中创建任务需要项目的写权限
+	if req.ProjectID != nil {
+		if _, err := s.projectDAO.GetByID(ctx, *req.ProjectID); err != nil {
+			return nil, err
+		}
+		allowed, err := hasProjectPermission(ctx, s.projectDAO, *req.ProjectID, userID, models.PermissionTaskWrite)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, ErrProjectForbidden
+		}
+	}
+	if err := s.validateTags(ctx, req.ProjectID, req.TagIDs); err != nil {
+		return nil, err
+	}
+
+	// 添加子任务相当于修改父任务的拆分，需要能修改父任务
+	if req.ParentID != nil {
+		parent, err := s.taskDAO.GetByIDWithAssociations(ctx, *req.ParentID)
+		if errors.Is(err, ErrTaskNotFound) {
+			return nil, ErrInvalidParentTask
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !sameProject(parent.ProjectID, req.ProjectID) {
+			return nil, ErrInvalidParentTask
 		}
-		return nil, fmt.Errorf("查询用户失败: %w", err)
-	}
-	
-	// 开始事务
-	tx := s.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+		if err := s.checkEditable(ctx, parent, userID); err != nil {
+			return nil, err
 		}
-	}()
-	
-	// 创建任务
+	}
+
 	task := &models.Task{
 		Title:       req.Title,
 		Description: req.Description,
@@ -53,109 +120,108 @@ func (s *TaskService) CreateTask(userID uint, req *models.TaskCreateRequest) (*m
 		DueDate:     req.DueDate,
 		Status:      models.TaskStatusPending, // 默认状态为待处理
 		UserID:      userID,
+		ProjectID:   req.ProjectID,
+		ParentID:    req.ParentID,
 	}
-	
-	if err := tx.Create(task).Error; err != nil {
-		tx.Rollback()
-		return nil, fmt.Errorf("创建任务失败: %w", err)
-	}
-	
-	// 关联标签（多对多关系）
-	if len(req.TagIDs) > 0 {
-		var tags []models.Tag
-		if err := tx.Where("id IN ?", req.TagIDs).Find(&tags).Error; err != nil {
-			tx.Rollback()
-			return nil, fmt.Errorf("查询标签失败: %w", err)
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		taskDAO := s.taskDAO.WithTx(tx)
+		if err := taskDAO.Create(ctx, task); err != nil {
+			return err
 		}
-		
-		// 关联标签到任务
-		if err := tx.Model(task).Association("Tags").Append(tags); err != nil {
-			tx.Rollback()
-			return nil, fmt.Errorf("关联标签失败: %w", err)
+		if err := taskDAO.AddTags(ctx, task.ID, req.TagIDs); err != nil {
+			return err
 		}
+		return s.activityDAO.WithTx(tx).Create(ctx, []models.TaskActivity{{
+			TaskID:   task.ID,
+			UserID:   userID,
+			Action:   models.ActivityActionCreated,
+			NewValue: task.Title,
+		}})
+	})
+	if err != nil {
+		return nil, err
 	}
-	
-	// 提交事务
-	if err := tx.Commit().Error; err != nil {
-		return nil, fmt.Errorf("提交事务失败: %w", err)
-	}
-	
-	// 预加载关联数据
-	if err := s.db.Preload("User").Preload("Tags").First(task, task.ID).Error; err != nil {
-		fmt.Printf("预加载任务关联数据失败: %v\n", err)
-	}
-	
-	// 缓存任务信息
-	cacheKey := redis.BuildCacheKey(redis.TaskCachePrefix, task.ID)
-	if err := s.cache.Set(cacheKey, task, time.Hour); err != nil {
-		fmt.Printf("缓存任务信息失败: %v\n", err)
-	}
-	
-	// 清除用户任务列表缓存
-	userTasksKey := redis.BuildCacheKey(redis.UserTasksPrefix, userID)
-	if err := s.cache.Delete(userTasksKey); err != nil {
-		fmt.Printf("清除用户任务缓存失败: %v\n", err)
-	}
-	
-	// 更新任务统计计数器
-	s.updateTaskStats(userID, models.TaskStatusPending, 1)
-	
-	return task, nil
+
+	// 重新加载关联数据（用户、标签）
+	created, err := s.taskDAO.GetByIDWithAssociations(ctx, task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheTask(ctx, created)
+	s.clearUserTasksCache(ctx, userID)
+	if created.ParentID != nil {
+		s.clearTaskCache(ctx, *created.ParentID) // 父任务的子任务进度发生变化
+	}
+	s.updateTaskStats(ctx, userID, models.TaskStatusPending, 1)
+
+	return created, nil
 }
 
 // GetTaskByID 根据ID获取任务
-// 学习要点：预加载关联数据，缓存策略
-func (s *TaskService) GetTaskByID(id uint) (*models.Task, error) {
-	cacheKey := redis.BuildCacheKey(redis.TaskCachePrefix, id)
-	
-	// 尝试从缓存获取
-	var task models.Task
-	if err := s.cache.Get(cacheKey, &task); err == nil {
-		return &task, nil
-	}
-	
-	// 缓存未命中，从数据库查询（预加载关联数据）
-	if err := s.db.Preload("User").Preload("Tags").First(&task, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("任务不存在: ID=%d", id)
-		}
-		return nil, fmt.Errorf("查询任务失败: %w", err)
+// 学习要点：先查缓存，未命中再查数据库并回填缓存
+func (s *TaskService) GetTaskByID(ctx context.Context, id uint) (*models.Task, error) {
+	cacheKey := cache.BuildCacheKey(cache.TaskCachePrefix, id)
+
+	var cached models.Task
+	if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+		return &cached, nil
 	}
-	
-	// 更新缓存
-	if err := s.cache.Set(cacheKey, &task, time.Hour); err != nil {
-		fmt.Printf("缓存任务信息失败: %v\n", err)
+
+	task, err := s.taskDAO.GetByIDWithAssociations(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	
-	return &task, nil
+
+	s.cacheTask(ctx, task)
+	return task, nil
 }
 
 // UpdateTask 更新任务
-// 学习要点：部分更新，状态变更处理，关联数据更新
-func (s *TaskService) UpdateTask(id uint, userID uint, req *models.TaskUpdateRequest) (*models.Task, error) {
-	// 获取现有任务
-	task, err := s.GetTaskByID(id)
+// 学习要点：部分更新；状态变更必须符合状态流转表，并自动维护开始和结束时间
+func (s *TaskService) UpdateTask(ctx context.Context, id uint, userID uint, req *models.TaskUpdateRequest) (*models.Task, error) {
+	return s.updateTask(ctx, id, userID, req, models.ActivityActionUpdated)
+}
+
+// CompleteTask 标记任务为完成
+func (s *TaskService) CompleteTask(ctx context.Context, id uint, userID uint) (*models.Task, error) {
+	status := models.TaskStatusCompleted
+	return s.updateTask(ctx, id, userID, &models.TaskUpdateRequest{Status: &status}, models.ActivityActionCompleted)
+}
+
+// updateTask 更新任务并记录任务动态，action 区分普通修改和标记完成
+// 学习要点：前置任务没有全部结束时，不能把任务改为进行中或已完成
+func (s *TaskService) updateTask(ctx context.Context, id uint, userID uint, req *models.TaskUpdateRequest, action string) (*models.Task, error) {
+	// 直接查数据库：权限判断不能依赖可能过期的缓存；同时加载标签用于记录标签变化
+	task, err := s.taskDAO.GetByIDWithAssociations(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	
-	// 验证权限（只有任务创建者可以修改）
-	if task.UserID != userID {
-		return nil, fmt.Errorf("没有权限修改此任务")
-	}
-	
-	// 开始事务
-	tx := s.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+
+	// 验证权限（创建者、负责人和项目维护者可以修改）
+	if err := s.checkEditable(ctx, task, userID); err != nil {
+		return nil, err
+	}
+	if err := s.validateTags(ctx, task.ProjectID, req.TagIDs); err != nil {
+		return nil, err
+	}
+	var transition models.TaskTransition
+	if req.Status != nil && *req.Status != task.Status {
+		var ok bool
+		if transition, ok = models.FindTaskTransition(task.Status, *req.Status); !ok {
+			return nil, invalidTransitionError(task.Status, *req.Status)
+		}
+		if blocksStart(task.Status, *req.Status) {
+			if blockers := openBlockerIDs(task); len(blockers) > 0 {
+				return nil, taskBlockedError(blockers)
+			}
 		}
-	}()
-	
+	}
+
 	// 记录状态变更（用于统计计数器更新）
 	oldStatus := task.Status
-	
-	// 准备更新数据
+
 	updates := make(map[string]interface{})
 	if req.Title != nil {
 		updates["title"] = *req.Title
@@ -163,20 +229,19 @@ func (s *TaskService) UpdateTask(id uint, userID uint, req *models.TaskUpdateReq
 	if req.Description != nil {
 		updates["description"] = *req.Description
 	}
-	if req.Status != nil {
+	if req.Status != nil && *req.Status != task.Status {
 		updates["status"] = *req.Status
-		// 状态变更时的特殊处理
-		switch *req.Status {
-		case models.TaskStatusInProgress:
-			if task.StartTime == nil {
-				now := time.Now()
-				updates["start_time"] = &now
-			}
-		case models.TaskStatusCompleted:
-			if task.EndTime == nil {
-				now := time.Now()
-				updates["end_time"] = &now
-			}
+		// 状态变更时的特殊处理：开始时间保留第一次开始的时间；
+		// 每次完成或取消都更新结束时间，重新打开时清空结束时间
+		now := time.Now()
+		if transition.IsReopen() {
+			updates["end_time"] = (*time.Time)(nil)
+		}
+		if *req.Status == models.TaskStatusInProgress && task.StartTime == nil {
+			updates["start_time"] = &now
+		}
+		if *req.Status == models.TaskStatusCompleted || *req.Status == models.TaskStatusCancelled {
+			updates["end_time"] = &now
 		}
 	}
 	if req.Priority != nil {
@@ -191,126 +256,334 @@ func (s *TaskService) UpdateTask(id uint, userID uint, req *models.TaskUpdateReq
 	if req.DueDate != nil {
 		updates["due_date"] = req.DueDate
 	}
-	
-	// 执行更新
-	if err := tx.Model(task).Updates(updates).Error; err != nil {
-		tx.Rollback()
-		return nil, fmt.Errorf("更新任务失败: %w", err)
-	}
-	
-	// 更新标签关联
-	if req.TagIDs != nil {
-		// 清除现有标签关联
-		if err := tx.Model(task).Association("Tags").Clear(); err != nil {
-			tx.Rollback()
-			return nil, fmt.Errorf("清除标签关联失败: %w", err)
+
+	activities := buildTaskActivities(task, updates, req.TagIDs, userID, action)
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		taskDAO := s.taskDAO.WithTx(tx)
+		if _, ok := updates["status"]; ok {
+			// 状态流转按读取到的旧状态校验，更新时要求状态没有被并发请求修改
+			updated, err := taskDAO.UpdateFieldsIfStatus(ctx, id, oldStatus, updates)
+			if err != nil {
+				return err
+			}
+			if !updated {
+				return invalidTransitionError(oldStatus, *req.Status)
+			}
+		} else if err := taskDAO.UpdateFields(ctx, id, updates); err != nil {
+			return err
 		}
-		
-		// 添加新的标签关联
-		if len(req.TagIDs) > 0 {
-			var tags []models.Tag
-			if err := tx.Where("id IN ?", req.TagIDs).Find(&tags).Error; err != nil {
-				tx.Rollback()
-				return nil, fmt.Errorf("查询标签失败: %w", err)
+		// TagIDs 为 nil 表示不修改标签，空切片表示清空标签
+		if req.TagIDs != nil {
+			if err := taskDAO.ReplaceTags(ctx, id, req.TagIDs); err != nil {
+				return err
 			}
-			
-			if err := tx.Model(task).Association("Tags").Append(tags); err != nil {
-				tx.Rollback()
-				return nil, fmt.Errorf("关联标签失败: %w", err)
+		}
+		return s.activityDAO.WithTx(tx).Create(ctx, activities)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.taskDAO.GetByIDWithAssociations(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 删除缓存（让下次查询时重新缓存）
+	// 学习要点：修改者可能是负责人，任务列表缓存和统计计数器都属于创建者
+	s.clearTaskCache(ctx, id)
+	s.clearUserTasksCache(ctx, task.UserID)
+
+	// 更新任务统计（如果状态发生变更）
+	// 学习要点：状态变化会影响父任务的进度和后续任务的阻塞状态，一并清除它们的缓存
+	if req.Status != nil && oldStatus != *req.Status {
+		s.updateTaskStats(ctx, task.UserID, oldStatus, -1)
+		s.updateTaskStats(ctx, task.UserID, *req.Status, 1)
+		s.clearRelatedTaskCache(ctx, task)
+	}
+
+	return updated, nil
+}
+
+// AssignTask 指派任务负责人，已经是负责人的用户被忽略
+// 学习要点：创建者和现有负责人都可以指派，负责人变化记录到任务动态
+func (s *TaskService) AssignTask(ctx context.Context, id uint, userID uint, assigneeIDs []uint) (*models.Task, error) {
+	task, err := s.taskDAO.GetByIDWithAssociations(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkEditable(ctx, task, userID); err != nil {
+		return nil, err
+	}
+
+	// 被指派的用户必须存在
+	users, err := s.userDAO.GetByIDs(ctx, assigneeIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) != len(uniqueIDs(assigneeIDs)) {
+		return nil, ErrInvalidAssignee
+	}
+	// 项目中的任务只能指派给项目成员
+	if task.ProjectID != nil {
+		for _, assigneeID := range uniqueIDs(assigneeIDs) {
+			allowed, err := hasProjectPermission(ctx, s.projectDAO, *task.ProjectID, assigneeID, models.PermissionTaskWrite)
+			if err != nil {
+				return nil, err
+			}
+			if !allowed {
+				return nil, ErrInvalidAssignee
 			}
 		}
 	}
-	
-	// 提交事务
-	if err := tx.Commit().Error; err != nil {
-		return nil, fmt.Errorf("提交事务失败: %w", err)
+
+	activities := buildAssigneeActivities(task, append(userIDsOf(task.Assignees), assigneeIDs...), userID)
+	return s.changeTaskRelations(ctx, task, activities, func(taskDAO dao.TaskDAO) error {
+		return taskDAO.AddAssignees(ctx, id, assigneeIDs)
+	})
+}
+
+// UnassignTask 移除任务负责人，负责人也可以移除自己
+func (s *TaskService) UnassignTask(ctx context.Context, id uint, userID uint, assigneeID uint) (*models.Task, error) {
+	task, err := s.taskDAO.GetByIDWithAssociations(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	
-	// 重新加载任务数据
-	if err := s.db.Preload("User").Preload("Tags").First(task, id).Error; err != nil {
-		return nil, fmt.Errorf("重新加载任务数据失败: %w", err)
+	if err := s.checkEditable(ctx, task, userID); err != nil {
+		return nil, err
 	}
-	
-	// 删除缓存（让下次查询时重新缓存）
-	cacheKey := redis.BuildCacheKey(redis.TaskCachePrefix, id)
-	if err := s.cache.Delete(cacheKey); err != nil {
-		fmt.Printf("删除任务缓存失败: %v\n", err)
+
+	remaining := slices.DeleteFunc(userIDsOf(task.Assignees), func(assigned uint) bool { return assigned == assigneeID })
+	activities := buildAssigneeActivities(task, remaining, userID)
+	return s.changeTaskRelations(ctx, task, activities, func(taskDAO dao.TaskDAO) error {
+		return taskDAO.RemoveAssignee(ctx, id, assigneeID)
+	})
+}
+
+// changeTaskRelations 在同一个事务中修改负责人或前置任务并记录任务动态
+func (s *TaskService) changeTaskRelations(ctx context.Context, task *models.Task, activities []models.TaskActivity, change func(taskDAO dao.TaskDAO) error) (*models.Task, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := change(s.taskDAO.WithTx(tx)); err != nil {
+			return err
+		}
+		return s.activityDAO.WithTx(tx).Create(ctx, activities)
+	})
+	if err != nil {
+		return nil, err
 	}
-	
-	// 清除用户任务列表缓存
-	userTasksKey := redis.BuildCacheKey(redis.UserTasksPrefix, userID)
-	if err := s.cache.Delete(userTasksKey); err != nil {
-		fmt.Printf("清除用户任务缓存失败: %v\n", err)
+
+	s.clearTaskCache(ctx, task.ID)
+	return s.taskDAO.GetByIDWithAssociations(ctx, task.ID)
+}
+
+// AddDependency 添加前置任务：blockerID 对应的任务结束前，任务不能开始或完成
+// 学习要点：前置任务必须与任务属于同一项目；写入前遍历已有依赖，拒绝会形成环的依赖
+func (s *TaskService) AddDependency(ctx context.Context, id uint, userID uint, blockerID uint) (*models.Task, error) {
+	task, err := s.taskDAO.GetByIDWithAssociations(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	
-	// 更新任务统计（如果状态发生变更）
-	if req.Status != nil && oldStatus != *req.Status {
-		s.updateTaskStats(userID, oldStatus, -1)    // 减少原状态计数
-		s.updateTaskStats(userID, *req.Status, 1)   // 增加新状态计数
+	if err := s.checkEditable(ctx, task, userID); err != nil {
+		return nil, err
 	}
-	
-	return task, nil
+
+	blocker, err := s.taskDAO.GetByID(ctx, blockerID)
+	if errors.Is(err, ErrTaskNotFound) {
+		return nil, ErrInvalidDependency
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !sameProject(task.ProjectID, blocker.ProjectID) {
+		return nil, ErrInvalidDependency
+	}
+
+	cycle, err := findDependencyCycle(ctx, id, blockerID, s.taskDAO.GetBlockerIDs)
+	if err != nil {
+		return nil, err
+	}
+	if cycle != nil {
+		return nil, dependencyCycleError(cycle)
+	}
+
+	activities := buildDependencyActivities(task, append(taskIDsOf(task.BlockedBy), blockerID), userID)
+	return s.changeTaskRelations(ctx, task, activities, func(taskDAO dao.TaskDAO) error {
+		return taskDAO.AddDependency(ctx, id, blockerID)
+	})
+}
+
+// RemoveDependency 移除前置任务
+func (s *TaskService) RemoveDependency(ctx context.Context, id uint, userID uint, blockerID uint) (*models.Task, error) {
+	task, err := s.taskDAO.GetByIDWithAssociations(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkEditable(ctx, task, userID); err != nil {
+		return nil, err
+	}
+
+	remaining := slices.DeleteFunc(taskIDsOf(task.BlockedBy), func(blocked uint) bool { return blocked == blockerID })
+	activities := buildDependencyActivities(task, remaining, userID)
+	return s.changeTaskRelations(ctx, task, activities, func(taskDAO dao.TaskDAO) error {
+		return taskDAO.RemoveDependency(ctx, id, blockerID)
+	})
 }
 
-// DeleteTask 删除任务
-func (s *TaskService) DeleteTask(id uint, userID uint) error {
-	// 获取任务
-	task, err := s.GetTaskByID(id)
+// WatchTask 关注任务
+// 学习要点：关注者只能查看任务，不获得修改权限，因此只读角色也可以关注；项目任务要求能查看该项目
+func (s *TaskService) WatchTask(ctx context.Context, id uint, userID uint, role string) error {
+	task, err := s.taskDAO.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
-	
-	// 验证权限
+	if err := authorizeProjectItem(ctx, s.projectDAO, task.ProjectID, userID, role, models.PermissionTaskRead); err != nil {
+		return err
+	}
+	if err := s.taskDAO.AddWatcher(ctx, id, userID); err != nil {
+		return err
+	}
+	s.clearTaskCache(ctx, id)
+	return nil
+}
+
+// UnwatchTask 取消关注任务
+func (s *TaskService) UnwatchTask(ctx context.Context, id uint, userID uint) error {
+	if _, err := s.taskDAO.GetByID(ctx, id); err != nil {
+		return err
+	}
+	if err := s.taskDAO.RemoveWatcher(ctx, id, userID); err != nil {
+		return err
+	}
+	s.clearTaskCache(ctx, id)
+	return nil
+}
+
+// checkEditable 校验用户能否修改任务：除创建者和负责人外，项目维护者可以修改项目中的所有任务
+// 学习要点：项目任务先校验项目权限，被移出项目的创建者和负责人不能再修改任务
+func (s *TaskService) checkEditable(ctx context.Context, task *models.Task, userID uint) error {
+	if task.ProjectID == nil {
+		if canEditTask(task, userID) {
+			return nil
+		}
+		return ErrTaskForbidden
+	}
+
+	member, err := s.projectDAO.GetMember(ctx, *task.ProjectID, userID)
+	if errors.Is(err, dao.ErrProjectMemberNotFound) {
+		return ErrProjectForbidden
+	}
+	if err != nil {
+		return err
+	}
+	if !models.ProjectRoleHasPermission(member.Role, models.PermissionTaskWrite) {
+		return ErrProjectForbidden
+	}
+	if canEditTask(task, userID) || models.ProjectRoleHasPermission(member.Role, models.PermissionProjectManage) {
+		return nil
+	}
+	return ErrTaskForbidden
+}
+
+// validateTags 校验标签存在，并且是全局标签或属于任务所在的项目
+// 学习要点：项目标签只在本项目内使用，防止把其他项目的标签挂到任务上
+func (s *TaskService) validateTags(ctx context.Context, projectID *uint, tagIDs []uint) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	tags, err := s.tagDAO.GetByIDs(ctx, tagIDs)
+	if err != nil {
+		return err
+	}
+	if len(tags) != len(uniqueIDs(tagIDs)) {
+		return ErrInvalidTaskTag
+	}
+	for _, tag := range tags {
+		if tag.ProjectID != nil && (projectID == nil || *tag.ProjectID != *projectID) {
+			return ErrInvalidTaskTag
+		}
+	}
+	return nil
+}
+
+// canEditTask 判断用户能否修改任务：创建者和负责人可以修改，关注者只能查看
+// 学习要点：task 需要预加载 Assignees
+func canEditTask(task *models.Task, userID uint) bool {
+	if task.UserID == userID {
+		return true
+	}
+	return slices.Contains(userIDsOf(task.Assignees), userID)
+}
+
+// DeleteTask 删除任务（软删除）
+func (s *TaskService) DeleteTask(ctx context.Context, id uint, userID uint) error {
+	task, err := s.taskDAO.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// 验证权限：只有创建者可以删除，项目任务还要求创建者仍有项目的写权限
 	if task.UserID != userID {
-		return fmt.Errorf("没有权限删除此任务")
-	}
-	
-	// 开始事务
-	tx := s.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+		return ErrTaskForbidden
+	}
+	if task.ProjectID != nil {
+		allowed, err := hasProjectPermission(ctx, s.projectDAO, *task.ProjectID, userID, models.PermissionTaskWrite)
+		if err != nil {
+			return err
 		}
-	}()
-	
-	// 删除标签关联
-	if err := tx.Model(task).Association("Tags").Clear(); err != nil {
-		tx.Rollback()
-		return fmt.Errorf("清除标签关联失败: %w", err)
-	}
-	
-	// 软删除任务
-	if err := tx.Delete(task).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("删除任务失败: %w", err)
-	}
-	
-	// 提交事务
-	if err := tx.Commit().Error; err != nil {
-		return fmt.Errorf("提交事务失败: %w", err)
-	}
-	
-	// 删除相关缓存
-	cacheKey := redis.BuildCacheKey(redis.TaskCachePrefix, id)
-	if err := s.cache.Delete(cacheKey); err != nil {
-		fmt.Printf("删除任务缓存失败: %v\n", err)
-	}
-	
-	// 清除用户任务列表缓存
-	userTasksKey := redis.BuildCacheKey(redis.UserTasksPrefix, userID)
-	if err := s.cache.Delete(userTasksKey); err != nil {
-		fmt.Printf("清除用户任务缓存失败: %v\n", err)
-	}
-	
-	// 更新任务统计
-	s.updateTaskStats(userID, task.Status, -1)
-	
+		if !allowed {
+			return ErrProjectForbidden
+		}
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		taskDAO := s.taskDAO.WithTx(tx)
+		// 清除标签关联
+		if err := taskDAO.ReplaceTags(ctx, id, nil); err != nil {
+			return err
+		}
+		return taskDAO.Delete(ctx, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.clearTaskCache(ctx, id)
+	s.clearUserTasksCache(ctx, userID)
+	s.clearRelatedTaskCache(ctx, task)
+	s.updateTaskStats(ctx, userID, task.Status, -1)
+
 	return nil
 }
 
+// ListTaskActivities 游标分页获取任务动态（最新的在前），项目任务要求能查看该项目
+func (s *TaskService) ListTaskActivities(ctx context.Context, taskID, userID uint, role string, query models.CursorQuery) (*models.CursorPage, error) {
+	beforeID, err := decodeCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	task, err := s.taskDAO.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeProjectItem(ctx, s.projectDAO, task.ProjectID, userID, role, models.PermissionTaskRead); err != nil {
+		return nil, err
+	}
+
+	limit := query.GetLimit()
+	activities, err := s.activityDAO.ListByTaskID(ctx, taskID, beforeID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := models.NewCursorPage(activities, limit, func(a models.TaskActivity) uint { return a.ID })
+	return &page, nil
+}
+
 // QueryTasks 查询任务（支持多种过滤条件和分页）
-// 学习要点：复杂查询构建，动态条件，关联查询优化
-func (s *TaskService) QueryTasks(req *models.TaskQueryRequest) (*models.PageResult, error) {
+// 学习要点：请求参数转换为DAO过滤器，查询构建由DAO负责；
+// 项目中的任务只对项目成员可见，按项目查询时先校验成员身份
+func (s *TaskService) QueryTasks(ctx context.Context, req *models.TaskQueryRequest) (*models.PageResult, error) {
 	// 设置默认分页参数
 	if req.Page <= 0 {
 		req.Page = 1
@@ -318,161 +591,234 @@ func (s *TaskService) QueryTasks(req *models.TaskQueryRequest) (*models.PageResu
 	if req.PageSize <= 0 {
 		req.PageSize = 10
 	}
-	
-	// 构建查询
-	query := s.db.Model(&models.Task{})
-	
-	// 添加查询条件
-	if req.Status != nil {
-		query = query.Where("status = ?", *req.Status)
-	}
-	if req.Priority != nil {
-		query = query.Where("priority = ?", *req.Priority)
-	}
-	if req.UserID != nil {
-		query = query.Where("user_id = ?", *req.UserID)
-	}
-	if req.TagID != nil {
-		// 关联查询：查找包含指定标签的任务
-		query = query.Joins("JOIN task_tags ON tasks.id = task_tags.task_id").
-			Where("task_tags.tag_id = ?", *req.TagID)
+
+	filter := dao.TaskFilter{
+		UserID:     req.UserID,
+		AssigneeID: req.AssigneeID,
+		ProjectID:  req.ProjectID,
+		Status:     req.Status,
+		Priority:   req.Priority,
+		TagID:      req.TagID,
+		Keyword:    req.Keyword,
+		OrderBy:    "created_at",
+		OrderDesc:  true,
+		Page:       req.Page,
+		PageSize:   req.PageSize,
+	}
+	if !req.ViewAllProjects {
+		if req.ProjectID != nil {
+			allowed, err := hasProjectPermission(ctx, s.projectDAO, *req.ProjectID, req.ViewerID, models.PermissionTaskRead)
+			if err != nil {
+				return nil, err
+			}
+			if !allowed {
+				return nil, ErrProjectForbidden
+			}
+		} else {
+			filter.VisibleTo = &req.ViewerID
+		}
 	}
-	if req.Keyword != "" {
-		// 模糊搜索：标题或描述包含关键词
-		keyword := "%" + req.Keyword + "%"
-		query = query.Where("title LIKE ? OR description LIKE ?", keyword, keyword)
+
+	tasks, total, err := s.taskDAO.GetTasksByFilter(ctx, filter)
+	if err != nil {
+		return nil, err
 	}
-	
-	// 查询总数
-	var total int64
-	if err := query.Count(&total).Error; err != nil {
-		return nil, fmt.Errorf("查询任务总数失败: %w", err)
-	}
-	
-	// 分页查询
-	var tasks []models.Task
-	offset := (req.Page - 1) * req.PageSize
-	if err := query.Preload("User").Preload("Tags").
-		Limit(req.PageSize).Offset(offset).
-		Order("created_at DESC").
-		Find(&tasks).Error; err != nil {
-		return nil, fmt.Errorf("查询任务列表失败: %w", err)
-	}
-	
-	// 构建返回结果
-	result := &models.PageResult{
+
+	return &models.PageResult{
 		List: tasks,
 		PageInfo: models.PageInfo{
 			Page:     req.Page,
 			PageSize: req.PageSize,
 			Total:    total,
 		},
-	}
-	
-	return result, nil
+	}, nil
 }
 
-// GetUserTaskStats 获取用户任务统计
-// 学习要点：统计查询，缓存计数器使用
-func (s *TaskService) GetUserTaskStats(userID uint) (map[string]int64, error) {
+// GetUserTaskStats 获取用户任务统计，只统计查看者可见的任务（管理员可以看到全部）
+// 学习要点：一次分组查询得到各状态数量，没有任务的状态补0；
+// 与 QueryTasks 一致，项目中的任务只对项目成员可见，避免泄露其他项目的任务数量
+func (s *TaskService) GetUserTaskStats(ctx context.Context, userID, viewerID uint, role string) (map[string]int64, error) {
+	taskDAO := s.taskDAO
+	scoped := role != models.RoleAdmin
+	if scoped {
+		taskDAO = taskDAO.VisibleTo(viewerID)
+	}
+
+	byStatus, err := taskDAO.GetUserTaskStats(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	stats := make(map[string]int64)
-	
-	// 尝试从缓存获取统计数据
-	cacheKey := redis.BuildCacheKey(redis.TaskCountPrefix, userID)
-	if err := s.cache.HGetAll(cacheKey); err == nil {
-		// 如果缓存存在且有数据，直接返回
-		// 这里简化处理，实际项目中需要转换数据类型
-	}
-	
-	// 从数据库统计各状态的任务数量
-	statusList := []int{
+	var total int64
+	for _, status := range []int{
 		models.TaskStatusPending,
 		models.TaskStatusInProgress,
 		models.TaskStatusCompleted,
 		models.TaskStatusCancelled,
-	}
-	
-	for _, status := range statusList {
-		var count int64
-		if err := s.db.Model(&models.Task{}).
-			Where("user_id = ? AND status = ?", userID, status).
-			Count(&count).Error; err != nil {
-			return nil, fmt.Errorf("统计状态 %d 的任务数量失败: %w", status, err)
-		}
-		
-		// 根据状态设置统计键名
-		var key string
-		switch status {
-		case models.TaskStatusPending:
-			key = "pending"
-		case models.TaskStatusInProgress:
-			key = "in_progress"
-		case models.TaskStatusCompleted:
-			key = "completed"
-		case models.TaskStatusCancelled:
-			key = "cancelled"
-		}
+	} {
+		count := byStatus[status]
+		key := models.StatusKey(status)
 		stats[key] = count
-		
-		// 更新缓存计数器
-		countKey := fmt.Sprintf("%s%d:%s", redis.TaskCountPrefix, userID, key)
-		if err := s.cache.Set(countKey, count, time.Hour*24); err != nil {
-			fmt.Printf("缓存任务统计失败: %v\n", err)
+		total += count
+
+		// 用数据库结果校准缓存计数器（计数器统计用户的全部任务，按可见范围过滤的结果不能用于校准）
+		if !scoped {
+			countKey := fmt.Sprintf("%s%d:%s", cache.TaskCountPrefix, userID, key)
+			if err := s.cache.Set(ctx, countKey, count, s.live.Cache().GetTaskCountTTL()); err != nil {
+				slog.WarnContext(ctx, "缓存任务统计失败", "error", err)
+			}
 		}
 	}
-	
-	// 统计总任务数
-	var totalCount int64
-	if err := s.db.Model(&models.Task{}).
-		Where("user_id = ?", userID).
-		Count(&totalCount).Error; err != nil {
-		return nil, fmt.Errorf("统计总任务数量失败: %w", err)
-	}
-	stats["total"] = totalCount
-	
-	// 统计过期任务数
-	var overdueCount int64
-	if err := s.db.Model(&models.Task{}).
-		Where("user_id = ? AND due_date < ? AND status != ?", 
-			userID, time.Now(), models.TaskStatusCompleted).
-		Count(&overdueCount).Error; err != nil {
-		return nil, fmt.Errorf("统计过期任务数量失败: %w", err)
-	}
-	stats["overdue"] = overdueCount
-	
+	stats["total"] = total
+
+	stats["overdue"], err = taskDAO.CountOverdueByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// GetTaskStats 获取任务统计，projectID 为 nil 时统计全部任务
+// 学习要点：多个聚合查询组合成一个仪表盘结果，缺失的日期补0；
+// 项目统计使用 InProject 得到的DAO，统计查询本身不用区分是否按项目过滤
+func (s *TaskService) GetTaskStats(ctx context.Context, trendDays int, projectID *uint) (*models.TaskStats, error) {
+	cacheKey := taskStatsCacheKey(projectID, trendDays)
+	var cached models.TaskStats
+	if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+		return &cached, nil
+	}
+
+	taskDAO := s.taskDAO
+	if projectID != nil {
+		taskDAO = taskDAO.InProject(*projectID)
+	}
+
+	stats := &models.TaskStats{
+		ByStatus:   make(map[string]int64),
+		ByPriority: make(map[string]int64),
+		TrendDays:  trendDays,
+	}
+
+	// 按状态统计（同时累加总数）
+	statusStats, err := taskDAO.GetStatusStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for status, count := range statusStats {
+		stats.ByStatus[models.StatusKey(status)] = count
+		stats.Total += count
+	}
+
+	// 按优先级统计
+	priorityStats, err := taskDAO.GetPriorityStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for priority, count := range priorityStats {
+		stats.ByPriority[models.PriorityKey(priority)] = count
+	}
+
+	// 过期任务数
+	stats.Overdue, err = taskDAO.CountOverdue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 每日完成数量
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	since := today.AddDate(0, 0, -(trendDays - 1))
+	trend, err := taskDAO.GetCompletionTrend(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	stats.CompletionTrend = fillDailyCounts(trend, since, trendDays)
+
+	if err := s.cache.Set(ctx, cacheKey, stats, s.live.Cache().GetStatsTTL()); err != nil {
+		slog.WarnContext(ctx, "缓存任务统计失败", "error", err)
+	}
+
 	return stats, nil
 }
 
+// taskStatsCacheKey 任务统计缓存键：stats:tasks:all:<天数> 或 stats:tasks:project:<项目ID>:<天数>
+func taskStatsCacheKey(projectID *uint, trendDays int) string {
+	if projectID == nil {
+		return fmt.Sprintf("%stasks:all:%d", cache.StatsCachePrefix, trendDays)
+	}
+	return fmt.Sprintf("%stasks:project:%d:%d", cache.StatsCachePrefix, *projectID, trendDays)
+}
+
+// fillDailyCounts 补齐没有数据的日期，保证前端图表横轴连续
+func fillDailyCounts(counts []models.DailyCount, since time.Time, days int) []models.DailyCount {
+	byDate := make(map[string]int64, len(counts))
+	for _, c := range counts {
+		byDate[c.Date] = c.Count
+	}
+
+	result := make([]models.DailyCount, 0, days)
+	for i := 0; i < days; i++ {
+		date := since.AddDate(0, 0, i).Format("2006-01-02")
+		result = append(result, models.DailyCount{Date: date, Count: byDate[date]})
+	}
+	return result
+}
+
 // updateTaskStats 更新任务统计计数器
 // 学习要点：Redis计数器的使用，原子操作
-func (s *TaskService) updateTaskStats(userID uint, status int, delta int64) {
-	var key string
-	switch status {
-	case models.TaskStatusPending:
-		key = "pending"
-	case models.TaskStatusInProgress:
-		key = "in_progress"
-	case models.TaskStatusCompleted:
-		key = "completed"
-	case models.TaskStatusCancelled:
-		key = "cancelled"
-	default:
+func (s *TaskService) updateTaskStats(ctx context.Context, userID uint, status int, delta int64) {
+	key := models.StatusKey(status)
+	if key == "unknown" {
 		return
 	}
-	
-	countKey := fmt.Sprintf("%s%d:%s", redis.TaskCountPrefix, userID, key)
-	if delta > 0 {
-		if _, err := s.cache.IncrBy(countKey, delta); err != nil {
-			fmt.Printf("增加任务统计计数失败: %v\n", err)
-		}
-	} else {
-		if _, err := s.cache.DecrBy(countKey, -delta); err != nil {
-			fmt.Printf("减少任务统计计数失败: %v\n", err)
-		}
+
+	countKey := fmt.Sprintf("%s%d:%s", cache.TaskCountPrefix, userID, key)
+	if _, err := s.cache.IncrBy(ctx, countKey, delta); err != nil {
+		slog.WarnContext(ctx, "更新任务统计计数失败", "error", err)
+		return
 	}
-	
+
 	// 设置过期时间
-	if err := s.cache.SetExpire(countKey, time.Hour*24); err != nil {
-		fmt.Printf("设置统计计数过期时间失败: %v\n", err)
+	if err := s.cache.SetExpire(ctx, countKey, s.live.Cache().GetTaskCountTTL()); err != nil {
+		slog.WarnContext(ctx, "设置统计计数过期时间失败", "error", err)
+	}
+}
+
+// 缓存相关方法
+func (s *TaskService) cacheTask(ctx context.Context, task *models.Task) {
+	cacheKey := cache.BuildCacheKey(cache.TaskCachePrefix, task.ID)
+	if err := s.cache.Set(ctx, cacheKey, task, s.live.Cache().GetTaskTTL()); err != nil {
+		slog.WarnContext(ctx, "缓存任务信息失败", "error", err)
+	}
+}
+
+func (s *TaskService) clearTaskCache(ctx context.Context, id uint) {
+	cacheKey := cache.BuildCacheKey(cache.TaskCachePrefix, id)
+	if err := s.cache.Delete(ctx, cacheKey); err != nil {
+		slog.WarnContext(ctx, "删除任务缓存失败", "error", err)
+	}
+}
+
+// clearRelatedTaskCache 清除父任务和后续任务的缓存：详情中的子任务进度和前置任务状态依赖本任务
+func (s *TaskService) clearRelatedTaskCache(ctx context.Context, task *models.Task) {
+	if task.ParentID != nil {
+		s.clearTaskCache(ctx, *task.ParentID)
+	}
+	blockedIDs, err := s.taskDAO.GetBlockedTaskIDs(ctx, task.ID)
+	if err != nil {
+		slog.WarnContext(ctx, "查询后续任务失败", "error", err)
+		return
+	}
+	for _, id := range blockedIDs {
+		s.clearTaskCache(ctx, id)
 	}
-}
\ No newline at end of file
+}
+
+func (s *TaskService) clearUserTasksCache(ctx context.Context, userID uint) {
+	cacheKey := cache.BuildCacheKey(cache.UserTasksPrefix, userID)
+	if err := s.cache.Delete(ctx, cacheKey); err != nil {
+		slog.WarnContext(ctx, "清除用户任务缓存失败", "error", err)
+	}
+}
@@ -1,146 +1,197 @@
 // Package services 业务逻辑层
-// 学习要点：服务层设计，业务逻辑封装，缓存策略
+// 学习要点：服务层通过DAO访问数据，业务逻辑与数据访问分离
 package services
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"gorm.io/gorm"
-	"task-management-system/internal/database"
+	"task-management-system/internal/apperr"
+	"task-management-system/internal/config"
+	"task-management-system/internal/dao"
 	"task-management-system/internal/models"
-	"task-management-system/pkg/redis"
+	"task-management-system/pkg/cache"
+	"task-management-system/pkg/utils"
 )
 
-// UserService 用户服务结构体
-// 学习要点：服务层结构设计，依赖注入
+// 用户相关错误
+var (
+	ErrUserNotFound     = dao.ErrUserNotFound
+	ErrUsernameExists   = apperr.Conflict("username_exists", "用户名已存在")
+	ErrEmailExists      = apperr.Conflict("email_exists", "邮箱已存在")
+	ErrInvalidRole      = apperr.Validation("invalid_role", "无效的角色")
+	ErrWrongOldPassword = apperr.Unauthorized("wrong_old_password", "原密码错误")
+)
+
+// UserService 用户服务
+// 学习要点：依赖注入，接口编程，测试友好
 type UserService struct {
-	db    *gorm.DB
-	cache *redis.CacheService
+	userDAO     dao.UserDAO
+	taskDAO     dao.TaskDAO
+	cache       cache.Cache
+	live        *config.Live // 缓存有效期等可热更新的配置
+	authService *AuthService // 修改密码后吊销已签发的令牌
+	db          *gorm.DB
 }
 
 // NewUserService 创建用户服务实例
-func NewUserService() *UserService {
+func NewUserService(db *gorm.DB, cache cache.Cache, live *config.Live, authService *AuthService) *UserService {
 	return &UserService{
-		db:    database.DB,
-		cache: redis.NewCacheService(),
+		userDAO:     dao.NewUserDAO(db),
+		taskDAO:     dao.NewTaskDAO(db),
+		cache:       cache,
+		live:        live,
+		authService: authService,
+		db:          db,
 	}
 }
 
 // CreateUser 创建用户
-// 学习要点：数据验证，事务处理，密码加密
-func (s *UserService) CreateUser(req *models.UserCreateRequest) (*models.User, error) {
-	// 检查用户名是否已存在
-	var existingUser models.User
-	if err := s.db.Where("username = ?", req.Username).First(&existingUser).Error; err == nil {
-		return nil, fmt.Errorf("用户名已存在: %s", req.Username)
-	} else if err != gorm.ErrRecordNotFound {
-		return nil, fmt.Errorf("检查用户名失败: %w", err)
+// 学习要点：业务逻辑处理，数据验证，事务使用
+func (s *UserService) CreateUser(ctx context.Context, req *models.UserCreateRequest) (*models.User, error) {
+	// 1. 数据验证（业务逻辑层职责）
+	if err := s.validateUserCreate(ctx, req); err != nil {
+		return nil, err
 	}
 	
-	// 检查邮箱是否已存在
-	if err := s.db.Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
-		return nil, fmt.Errorf("邮箱已存在: %s", req.Email)
-	} else if err != gorm.ErrRecordNotFound {
-		return nil, fmt.Errorf("检查邮箱失败: %w", err)
+	// 2. 加密密码
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		return nil, err
 	}
 	
-	// TODO: 在实际项目中应该加密密码
-	// password, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
-	// if err != nil {
-	//     return nil, fmt.Errorf("密码加密失败: %w", err)
-	// }
-	
-	// 创建用户
+	// 3. 构建用户对象
 	user := &models.User{
 		Username: req.Username,
 		Email:    req.Email,
-		Password: req.Password, // 实际项目中应该存储加密后的密码
+		Password: hashedPassword,
 		Nickname: req.Nickname,
 		Phone:    req.Phone,
-		Status:   1, // 默认状态为正常
+		Status:   1, // 默认状态
+		Role:     models.RoleMember,
 	}
 	
-	// 保存到数据库
-	if err := s.db.Create(user).Error; err != nil {
+	// 4. 保存到数据库（通过DAO）
+	if err := s.userDAO.Create(ctx, user); err != nil {
 		return nil, fmt.Errorf("创建用户失败: %w", err)
 	}
 	
-	// 缓存用户信息（缓存1小时）
-	cacheKey := redis.BuildCacheKey(redis.UserCachePrefix, user.ID)
-	if err := s.cache.Set(cacheKey, user.ToResponse(), time.Hour); err != nil {
-		// 缓存失败不影响主业务逻辑，只记录日志
-		fmt.Printf("缓存用户信息失败: %v\n", err)
-	}
+	// 5. 缓存用户信息（业务逻辑）
+	s.cacheUser(ctx, user)
 	
 	return user, nil
 }
 
 // GetUserByID 根据ID获取用户
-// 学习要点：缓存优先策略，缓存穿透处理
-func (s *UserService) GetUserByID(id uint) (*models.User, error) {
-	cacheKey := redis.BuildCacheKey(redis.UserCachePrefix, id)
-	
-	// 先尝试从缓存获取
-	var userResponse models.UserResponse
-	if err := s.cache.Get(cacheKey, &userResponse); err == nil {
-		// 从响应格式转换为完整用户模型
-		user := &models.User{
-			BaseModel: models.BaseModel{
-				ID:        userResponse.ID,
-				CreatedAt: userResponse.CreatedAt,
-				UpdatedAt: userResponse.UpdatedAt,
-			},
-			Username:    userResponse.Username,
-			Email:       userResponse.Email,
-			Nickname:    userResponse.Nickname,
-			Avatar:      userResponse.Avatar,
-			Phone:       userResponse.Phone,
-			Status:      userResponse.Status,
-			LastLoginAt: userResponse.LastLoginAt,
-		}
+// 学习要点：缓存策略，降级处理
+func (s *UserService) GetUserByID(ctx context.Context, id uint) (*models.User, error) {
+	// 1. 先尝试从缓存获取
+	if user := s.getUserFromCache(ctx, id); user != nil {
 		return user, nil
 	}
 	
-	// 缓存未命中，从数据库查询
-	var user models.User
-	if err := s.db.First(&user, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("用户不存在: ID=%d", id)
-		}
-		return nil, fmt.Errorf("查询用户失败: %w", err)
+	// 2. 从数据库查询（通过DAO）
+	user, err := s.userDAO.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 	
-	// 更新缓存
-	if err := s.cache.Set(cacheKey, user.ToResponse(), time.Hour); err != nil {
-		fmt.Printf("缓存用户信息失败: %v\n", err)
-	}
+	// 3. 更新缓存
+	s.cacheUser(ctx, user)
 	
-	return &user, nil
+	return user, nil
 }
 
 // GetUserByUsername 根据用户名获取用户
-func (s *UserService) GetUserByUsername(username string) (*models.User, error) {
-	var user models.User
-	if err := s.db.Where("username = ?", username).First(&user).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("用户不存在: %s", username)
-		}
-		return nil, fmt.Errorf("查询用户失败: %w", err)
+func (s *UserService) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	return s.userDAO.GetByUsername(ctx, username)
+}
+
+// VerifyPassword 校验用户名和密码
+// 学习要点：密码字段不进缓存，必须从数据库读取
+func (s *UserService) VerifyPassword(ctx context.Context, username, password string) (*models.User, error) {
+	user, err := s.userDAO.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, ErrInvalidCredentials
 	}
-	return &user, nil
+	if !utils.CheckPassword(user.Password, password) {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
 }
 
-// UpdateUser 更新用户信息
-// 学习要点：部分更新，缓存更新策略
-func (s *UserService) UpdateUser(id uint, req *models.UserUpdateRequest) (*models.User, error) {
-	// 查找用户
-	user, err := s.GetUserByID(id)
+// ChangePassword 修改密码（需要验证原密码）
+// 学习要点：修改密码后吊销之前签发的所有令牌，被盗用的会话不能在改密后继续使用
+func (s *UserService) ChangePassword(ctx context.Context, id uint, req *models.ChangePasswordRequest) error {
+	// 直接查数据库：缓存中的用户信息不包含密码
+	user, err := s.userDAO.GetByID(ctx, id)
 	if err != nil {
+		return err
+	}
+	
+	if !utils.CheckPassword(user.Password, req.OldPassword) {
+		return ErrWrongOldPassword
+	}
+	
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.userDAO.UpdatePassword(ctx, id, hashedPassword); err != nil {
+		return err
+	}
+	return s.authService.RevokeUserTokens(ctx, id)
+}
+
+// RehashPlaintextPasswords 将历史明文密码重新加密为 bcrypt 哈希
+// 学习要点：一次性数据迁移，分批处理避免一次加载全表，可重复执行（幂等）
+func (s *UserService) RehashPlaintextPasswords(ctx context.Context) (int, error) {
+	const batchSize = 100
+	rehashed := 0
+	
+	for offset := 0; ; offset += batchSize {
+		users, _, err := s.userDAO.List(ctx, offset, batchSize)
+		if err != nil {
+			return rehashed, err
+		}
+		
+		for _, user := range users {
+			// 已经是哈希的密码跳过，保证重复执行不会二次加密
+			if user.Password == "" || utils.IsBcryptHash(user.Password) {
+				continue
+			}
+			
+			hashedPassword, err := utils.HashPassword(user.Password)
+			if err != nil {
+				return rehashed, err
+			}
+			if err := s.userDAO.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+				return rehashed, err
+			}
+			rehashed++
+		}
+		
+		if len(users) < batchSize {
+			break
+		}
+	}
+	
+	return rehashed, nil
+}
+
+// UpdateUser 更新用户
+// 学习要点：部分更新，缓存失效
+func (s *UserService) UpdateUser(ctx context.Context, id uint, req *models.UserUpdateRequest) (*models.User, error) {
+	// 1. 确认用户存在
+	if _, err := s.userDAO.GetByID(ctx, id); err != nil {
 		return nil, err
 	}
 	
-	// 更新字段
+	// 2. 只收集需要修改的字段（业务逻辑）
 	updates := make(map[string]interface{})
 	if req.Nickname != "" {
 		updates["nickname"] = req.Nickname
@@ -152,76 +203,51 @@ func (s *UserService) UpdateUser(id uint, req *models.UserUpdateRequest) (*model
 		updates["phone"] = req.Phone
 	}
 	
-	// 执行更新
-	if err := s.db.Model(user).Updates(updates).Error; err != nil {
-		return nil, fmt.Errorf("更新用户失败: %w", err)
+	// 3. 只写入修改的列，避免覆盖并发修改的密码和状态
+	if err := s.userDAO.UpdateFields(ctx, id, updates); err != nil {
+		return nil, err
 	}
 	
-	// 删除缓存（让下次查询时重新缓存）
-	cacheKey := redis.BuildCacheKey(redis.UserCachePrefix, id)
-	if err := s.cache.Delete(cacheKey); err != nil {
-		fmt.Printf("删除用户缓存失败: %v\n", err)
-	}
+	// 4. 清除缓存
+	s.clearUserCache(ctx, id)
 	
-	return user, nil
+	return s.userDAO.GetByID(ctx, id)
 }
 
 // DeleteUser 删除用户（软删除）
-// 学习要点：软删除，关联数据处理，缓存清理
-func (s *UserService) DeleteUser(id uint) error {
-	// 开始事务
-	tx := s.db.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+// 学习要点：事务内通过 WithTx 获取带事务的DAO，缓存在事务提交之后再清理
+func (s *UserService) DeleteUser(ctx context.Context, id uint) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 创建带事务的DAO实例
+		userDAO := s.userDAO.WithTx(tx)
+		taskDAO := s.taskDAO.WithTx(tx)
+		
+		// 1. 检查用户是否存在
+		if _, err := userDAO.GetByID(ctx, id); err != nil {
+			return err
 		}
-	}()
-	
-	// 检查用户是否存在
-	var user models.User
-	if err := tx.First(&user, id).Error; err != nil {
-		tx.Rollback()
-		if err == gorm.ErrRecordNotFound {
-			return fmt.Errorf("用户不存在: ID=%d", id)
+		
+		// 2. 先删除用户的所有任务
+		if err := taskDAO.DeleteByUserID(ctx, id); err != nil {
+			return err
 		}
-		return fmt.Errorf("查询用户失败: %w", err)
-	}
-	
-	// 软删除用户的所有任务
-	if err := tx.Where("user_id = ?", id).Delete(&models.Task{}).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("删除用户任务失败: %w", err)
-	}
-	
-	// 软删除用户
-	if err := tx.Delete(&user).Error; err != nil {
-		tx.Rollback()
-		return fmt.Errorf("删除用户失败: %w", err)
-	}
-	
-	// 提交事务
-	if err := tx.Commit().Error; err != nil {
-		return fmt.Errorf("提交事务失败: %w", err)
-	}
-	
-	// 删除相关缓存
-	cacheKey := redis.BuildCacheKey(redis.UserCachePrefix, id)
-	if err := s.cache.Delete(cacheKey); err != nil {
-		fmt.Printf("删除用户缓存失败: %v\n", err)
+		
+		// 3. 删除用户
+		return userDAO.Delete(ctx, id)
+	})
+	if err != nil {
+		return err
 	}
 	
-	// 删除用户任务列表缓存
-	userTasksKey := redis.BuildCacheKey(redis.UserTasksPrefix, id)
-	if err := s.cache.Delete(userTasksKey); err != nil {
-		fmt.Printf("删除用户任务缓存失败: %v\n", err)
-	}
+	// 4. 事务提交后清除相关缓存，避免回滚时缓存已被清空或并发读到旧数据
+	s.clearUserCache(ctx, id)
+	s.clearUserTasksCache(ctx, id)
 	
 	return nil
 }
 
-// GetUserList 获取用户列表（分页）
-// 学习要点：分页查询，列表缓存策略
-func (s *UserService) GetUserList(page, pageSize int) (*models.PageResult, error) {
+// GetUserList 获取用户列表
+func (s *UserService) GetUserList(ctx context.Context, page, pageSize int) (*models.PageResult, error) {
 	if page <= 0 {
 		page = 1
 	}
@@ -232,25 +258,50 @@ func (s *UserService) GetUserList(page, pageSize int) (*models.PageResult, error
 	// 计算偏移量
 	offset := (page - 1) * pageSize
 	
-	// 查询总数
-	var total int64
-	if err := s.db.Model(&models.User{}).Count(&total).Error; err != nil {
-		return nil, fmt.Errorf("查询用户总数失败: %w", err)
+	// 通过DAO查询
+	users, total, err := s.userDAO.List(ctx, offset, pageSize)
+	if err != nil {
+		return nil, err
 	}
 	
-	// 查询用户列表
-	var users []models.User
-	if err := s.db.Limit(pageSize).Offset(offset).Find(&users).Error; err != nil {
-		return nil, fmt.Errorf("查询用户列表失败: %w", err)
+	// 转换为响应格式
+	var userResponses []models.UserResponse
+	for _, user := range users {
+		userResponses = append(userResponses, user.ToResponse())
 	}
 	
-	// 转换为响应格式
+	return &models.PageResult{
+		List: userResponses,
+		PageInfo: models.PageInfo{
+			Page:     page,
+			PageSize: pageSize,
+			Total:    total,
+		},
+	}, nil
+}
+
+// SearchUsers 搜索用户
+// 学习要点：搜索功能实现，缓存策略
+func (s *UserService) SearchUsers(ctx context.Context, keyword string, page, pageSize int) (*models.PageResult, error) {
+	// 检查缓存（可选）
+	cacheKey := fmt.Sprintf("search_users:%s:%d:%d", keyword, page, pageSize)
+	if result := s.getSearchResultFromCache(ctx, cacheKey); result != nil {
+		return result, nil
+	}
+	
+	// 通过DAO搜索
+	offset := (page - 1) * pageSize
+	users, total, err := s.userDAO.Search(ctx, keyword, offset, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	
+	// 构建结果
 	var userResponses []models.UserResponse
 	for _, user := range users {
 		userResponses = append(userResponses, user.ToResponse())
 	}
 	
-	// 构建分页结果
 	result := &models.PageResult{
 		List: userResponses,
 		PageInfo: models.PageInfo{
@@ -260,21 +311,225 @@ func (s *UserService) GetUserList(page, pageSize int) (*models.PageResult, error
 		},
 	}
 	
+	// 缓存搜索结果（短时间）
+	s.cacheSearchResult(ctx, cacheKey, result, s.live.Cache().GetUserSearchTTL())
+	
 	return result, nil
 }
 
-// UpdateLastLoginTime 更新用户最后登录时间
-func (s *UserService) UpdateLastLoginTime(id uint) error {
-	now := time.Now()
-	if err := s.db.Model(&models.User{}).Where("id = ?", id).Update("last_login_at", &now).Error; err != nil {
-		return fmt.Errorf("更新最后登录时间失败: %w", err)
+// GetActiveUsers 获取活跃用户
+// 学习要点：业务逻辑查询，缓存优化
+func (s *UserService) GetActiveUsers(ctx context.Context) ([]models.UserResponse, error) {
+	// 检查缓存
+	cacheKey := "active_users"
+	if users := s.getActiveUsersFromCache(ctx, cacheKey); users != nil {
+		return users, nil
+	}
+	
+	// 通过DAO查询
+	users, err := s.userDAO.GetActiveUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	// 转换格式
+	var responses []models.UserResponse
+	for _, user := range users {
+		responses = append(responses, user.ToResponse())
+	}
+	
+	// 缓存结果
+	s.cacheActiveUsers(ctx, cacheKey, responses, s.live.Cache().GetActiveUsersTTL())
+	
+	return responses, nil
+}
+
+// SetUsersStatus 批量启用或禁用用户
+// 学习要点：批量更新后逐个清除缓存，保证读到最新状态
+func (s *UserService) SetUsersStatus(ctx context.Context, ids []uint, status int) error {
+	if err := s.userDAO.BatchUpdateStatus(ctx, ids, status); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		s.clearUserCache(ctx, id)
+	}
+	return nil
+}
+
+// UpdateRole 修改用户角色
+func (s *UserService) UpdateRole(ctx context.Context, id uint, role string) (*models.User, error) {
+	if !models.IsValidRole(role) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRole, role)
+	}
+	
+	if _, err := s.userDAO.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	
+	// 只更新角色列，避免覆盖并发修改的密码和状态
+	if err := s.userDAO.UpdateRole(ctx, id, role); err != nil {
+		return nil, err
+	}
+	
+	s.clearUserCache(ctx, id)
+	return s.userDAO.GetByID(ctx, id)
+}
+
+// GetUserStats 获取用户统计
+// 学习要点：统计查询，多DAO协作，短TTL缓存降低仪表盘对数据库的压力
+func (s *UserService) GetUserStats(ctx context.Context, activeDays int) (*models.UserStats, error) {
+	cacheKey := fmt.Sprintf("%susers:%d", cache.StatsCachePrefix, activeDays)
+	var cached models.UserStats
+	if err := s.cache.Get(ctx, cacheKey, &cached); err == nil {
+		return &cached, nil
+	}
+	
+	stats := &models.UserStats{
+		ByStatus:   make(map[string]int64),
+		ActiveDays: activeDays,
+	}
+	
+	// 总用户数
+	_, total, err := s.userDAO.List(ctx, 0, 1)
+	if err != nil {
+		return nil, err
 	}
+	stats.Total = total
 	
-	// 删除缓存
-	cacheKey := redis.BuildCacheKey(redis.UserCachePrefix, id)
-	if err := s.cache.Delete(cacheKey); err != nil {
-		fmt.Printf("删除用户缓存失败: %v\n", err)
+	// 按状态统计
+	activeCount, err := s.userDAO.CountByStatus(ctx, 1)
+	if err != nil {
+		return nil, err
+	}
+	stats.ByStatus["active"] = activeCount
+	
+	disabledCount, err := s.userDAO.CountByStatus(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	stats.ByStatus["disabled"] = disabledCount
+	
+	// 按角色统计
+	stats.ByRole, err = s.userDAO.GetRoleStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	
+	// 最近N天登录过的用户数
+	since := time.Now().AddDate(0, 0, -activeDays)
+	stats.ActiveUsers, err = s.userDAO.CountActiveSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	
+	// 有任务的用户数
+	usersWithTasks, err := s.userDAO.GetUsersWithTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats.UsersWithTasks = len(usersWithTasks)
+	
+	if err := s.cache.Set(ctx, cacheKey, stats, s.live.Cache().GetStatsTTL()); err != nil {
+		slog.WarnContext(ctx, "缓存用户统计失败", "error", err)
+	}
+	
+	return stats, nil
+}
+
+// 私有方法：业务逻辑辅助方法
+
+// validateUserCreate 验证用户创建请求
+// 学习要点：只有明确"不存在"才算可用，数据库故障时不能当作校验通过
+func (s *UserService) validateUserCreate(ctx context.Context, req *models.UserCreateRequest) error {
+	// 检查用户名是否已存在
+	if _, err := s.userDAO.GetByUsername(ctx, req.Username); err == nil {
+		return fmt.Errorf("%w: %s", ErrUsernameExists, req.Username)
+	} else if !errors.Is(err, ErrUserNotFound) {
+		return fmt.Errorf("检查用户名失败: %w", err)
+	}
+	
+	// 检查邮箱是否已存在
+	if _, err := s.userDAO.GetByEmail(ctx, req.Email); err == nil {
+		return fmt.Errorf("%w: %s", ErrEmailExists, req.Email)
+	} else if !errors.Is(err, ErrUserNotFound) {
+		return fmt.Errorf("检查邮箱失败: %w", err)
 	}
 	
 	return nil
+}
+
+// 缓存相关方法
+func (s *UserService) cacheUser(ctx context.Context, user *models.User) {
+	cacheKey := cache.BuildCacheKey(cache.UserCachePrefix, user.ID)
+	if err := s.cache.Set(ctx, cacheKey, user.ToResponse(), s.live.Cache().GetUserTTL()); err != nil {
+		// 记录日志，不影响主流程
+		slog.WarnContext(ctx, "缓存用户信息失败", "error", err)
+	}
+}
+
+func (s *UserService) getUserFromCache(ctx context.Context, id uint) *models.User {
+	cacheKey := cache.BuildCacheKey(cache.UserCachePrefix, id)
+	var userResponse models.UserResponse
+	if err := s.cache.Get(ctx, cacheKey, &userResponse); err != nil {
+		return nil
+	}
+	
+	// 转换为完整用户对象（简化处理）
+	return &models.User{
+		BaseModel: models.BaseModel{
+			ID:        userResponse.ID,
+			CreatedAt: userResponse.CreatedAt,
+			UpdatedAt: userResponse.UpdatedAt,
+		},
+		Username:    userResponse.Username,
+		Email:       userResponse.Email,
+		Nickname:    userResponse.Nickname,
+		Avatar:      userResponse.Avatar,
+		Phone:       userResponse.Phone,
+		Status:      userResponse.Status,
+		Role:        userResponse.Role,
+		LastLoginAt: userResponse.LastLoginAt,
+	}
+}
+
+func (s *UserService) clearUserCache(ctx context.Context, id uint) {
+	cacheKey := cache.BuildCacheKey(cache.UserCachePrefix, id)
+	if err := s.cache.Delete(ctx, cacheKey); err != nil {
+		slog.WarnContext(ctx, "清除用户缓存失败", "error", err)
+	}
+}
+
+func (s *UserService) clearUserTasksCache(ctx context.Context, userID uint) {
+	cacheKey := cache.BuildCacheKey(cache.UserTasksPrefix, userID)
+	if err := s.cache.Delete(ctx, cacheKey); err != nil {
+		slog.WarnContext(ctx, "清除用户任务缓存失败", "error", err)
+	}
+}
+
+func (s *UserService) getSearchResultFromCache(ctx context.Context, key string) *models.PageResult {
+	var result models.PageResult
+	if err := s.cache.Get(ctx, key, &result); err != nil {
+		return nil
+	}
+	return &result
+}
+
+func (s *UserService) cacheSearchResult(ctx context.Context, key string, result *models.PageResult, duration time.Duration) {
+	if err := s.cache.Set(ctx, key, result, duration); err != nil {
+		slog.WarnContext(ctx, "缓存搜索结果失败", "error", err)
+	}
+}
+
+func (s *UserService) getActiveUsersFromCache(ctx context.Context, key string) []models.UserResponse {
+	var users []models.UserResponse
+	if err := s.cache.Get(ctx, key, &users); err != nil {
+		return nil
+	}
+	return users
+}
+
+func (s *UserService) cacheActiveUsers(ctx context.Context, key string, users []models.UserResponse, duration time.Duration) {
+	if err := s.cache.Set(ctx, key, users, duration); err != nil {
+		slog.WarnContext(ctx, "缓存活跃用户失败", "error", err)
+	}
 }
\ No newline at end of file
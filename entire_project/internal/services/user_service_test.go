@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-management-system/internal/auth"
+	"task-management-system/internal/config"
+	"task-management-system/internal/dao"
+	"task-management-system/internal/models"
+	"task-management-system/pkg/cache"
+	"task-management-system/pkg/utils"
+)
+
+// fakeUserDAO 内存中的单个用户，只实现修改密码用到的方法
+type fakeUserDAO struct {
+	dao.UserDAO
+	user *models.User
+}
+
+func (f *fakeUserDAO) GetByID(context.Context, uint) (*models.User, error) {
+	return f.user, nil
+}
+
+func (f *fakeUserDAO) UpdatePassword(_ context.Context, _ uint, hashedPassword string) error {
+	f.user.Password = hashedPassword
+	return nil
+}
+
+func TestUserService_ChangePassword_RevokesTokens(t *testing.T) {
+	ctx := context.Background()
+	tokenManager := auth.NewTokenManager(config.AuthConfig{Secret: "secret", Issuer: "test"})
+	authService := &AuthService{cache: cache.NewMemoryCache(0), tokenManager: tokenManager}
+
+	hashed, err := utils.HashPassword("old-password")
+	require.NoError(t, err)
+	users := &fakeUserDAO{user: &models.User{BaseModel: models.BaseModel{ID: 1}, Password: hashed}}
+	s := &UserService{userDAO: users, authService: authService}
+
+	pair, err := tokenManager.GenerateTokenPair(1, "alice", "member")
+	require.NoError(t, err)
+	stolen, err := tokenManager.ParseAccessToken(pair.AccessToken)
+	require.NoError(t, err)
+
+	req := &models.ChangePasswordRequest{OldPassword: "old-password", NewPassword: "new-password"}
+	require.NoError(t, s.ChangePassword(ctx, 1, req))
+	assert.True(t, utils.CheckPassword(users.user.Password, "new-password"))
+
+	revoked, err := authService.IsTokenRevoked(ctx, stolen)
+	require.NoError(t, err)
+	assert.True(t, revoked, "修改密码前签发的令牌失效")
+}
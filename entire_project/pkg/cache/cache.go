@@ -0,0 +1,76 @@
+// Package cache 缓存抽象
+// 学习要点：面向接口编程，业务代码只依赖 Cache 接口，
+// 具体使用 Redis 还是进程内存由配置决定，测试时无需启动 Redis
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrCacheMiss 缓存键、哈希字段不存在或列表为空
+// 学习要点：调用方用 errors.Is 区分"未命中"和"缓存故障"
+var ErrCacheMiss = errors.New("缓存未命中")
+
+// GetTTL 的特殊返回值（与 Redis 的 TTL 命令一致）
+const (
+	TTLNoExpire time.Duration = -1 // 键存在但没有设置过期时间
+	TTLNotFound time.Duration = -2 // 键不存在
+)
+
+// Cache 缓存接口
+// 学习要点：每个方法都接收 context，调用方的超时和取消可以传递到缓存操作；
+// 值统一以 JSON 序列化存储，读取时反序列化到 dest
+type Cache interface {
+	// 字符串
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Get(ctx context.Context, key string, dest interface{}) error
+	Delete(ctx context.Context, key string) error
+	Exists(ctx context.Context, key string) (bool, error)
+	SetExpire(ctx context.Context, key string, expiration time.Duration) error
+	GetTTL(ctx context.Context, key string) (time.Duration, error)
+
+	// 计数器
+	IncrBy(ctx context.Context, key string, value int64) (int64, error)
+	DecrBy(ctx context.Context, key string, value int64) (int64, error)
+
+	// 哈希
+	HSet(ctx context.Context, key, field string, value interface{}) error
+	HGet(ctx context.Context, key, field string, dest interface{}) error
+	HDel(ctx context.Context, key string, fields ...string) error
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+
+	// 列表
+	LPush(ctx context.Context, key string, values ...interface{}) error
+	RPop(ctx context.Context, key string, dest interface{}) error
+	LLen(ctx context.Context, key string) (int64, error)
+
+	// Ping 检查缓存是否可用（就绪探针使用）
+	Ping(ctx context.Context) error
+
+	// Close 释放底层资源
+	Close() error
+}
+
+// 缓存键常量定义
+// 学习要点：缓存键的标准化管理，避免键名冲突
+const (
+	UserCachePrefix     = "user:"           // 用户缓存前缀
+	TaskCachePrefix     = "task:"           // 任务缓存前缀
+	UserTasksPrefix     = "user_tasks:"     // 用户任务列表前缀
+	TaskCountPrefix     = "task_count:"     // 任务统计前缀
+	LoginAttemptsPrefix = "login_attempts:" // 登录尝试次数前缀
+	LoginLockPrefix     = "login_lock:"     // 登录锁定前缀
+	LoginBackoffPrefix  = "login_backoff:"  // 登录锁定次数前缀（用于指数退避）
+	RefreshTokenPrefix  = "refresh_token:"  // 刷新令牌前缀（存在即有效，删除即吊销）
+	TokenRevokedPrefix  = "token_revoked:"  // 用户令牌吊销时间前缀（强制下线）
+	StatsCachePrefix    = "stats:"          // 管理员统计数据前缀
+	RateLimitPrefix     = "ratelimit:"      // 限流计数前缀
+)
+
+// BuildCacheKey 构建缓存键
+func BuildCacheKey(prefix string, id interface{}) string {
+	return fmt.Sprintf("%s%v", prefix, id)
+}
@@ -0,0 +1,409 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"task-management-system/pkg/metrics"
+)
+
+// DefaultMemoryMaxEntries 内存缓存默认最多保存的键数量
+const DefaultMemoryMaxEntries = 10000
+
+// metricsDriver 缓存指标中的驱动标签
+const metricsDriver = "memory"
+
+// 内存缓存中值的类型（对应 Redis 的 string、hash、list）
+const (
+	kindString = iota
+	kindHash
+	kindList
+)
+
+// memoryEntry 内存缓存条目
+type memoryEntry struct {
+	key       string
+	kind      int
+	str       []byte
+	hash      map[string][]byte
+	list      [][]byte  // 下标0为列表左端
+	expiresAt time.Time // 零值表示永不过期
+}
+
+// expired 判断条目是否已过期
+func (e *memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// MemoryCache 进程内缓存，LRU淘汰 + TTL过期
+// 学习要点：双向链表 + 哈希表实现 O(1) 的 LRU；过期键在访问时惰性删除。
+// 注意：数据只存在于当前进程，多实例部署时各实例互不可见，只适合开发和测试
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List               // 最近使用的在前
+	items      map[string]*list.Element // 键 -> 链表节点
+	now        func() time.Time         // 便于测试时替换时钟
+}
+
+// NewMemoryCache 创建内存缓存，maxEntries <= 0 时使用默认容量
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMemoryMaxEntries
+	}
+	return &MemoryCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// 编译期检查 MemoryCache 实现了 Cache 接口
+var _ Cache = (*MemoryCache)(nil)
+
+// Set 设置缓存，expiration 为0表示永不过期
+func (m *MemoryCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("序列化数据失败: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := &memoryEntry{key: key, kind: kindString, str: data}
+	if expiration > 0 {
+		entry.expiresAt = m.now().Add(expiration)
+	}
+	m.put(entry)
+	return nil
+}
+
+// Get 获取缓存
+func (m *MemoryCache) Get(ctx context.Context, key string, dest interface{}) error {
+	m.mu.Lock()
+	entry, err := m.lookup(key, kindString)
+	var data []byte
+	if entry != nil {
+		data = entry.str
+	}
+	m.mu.Unlock()
+
+	if err != nil {
+		metrics.ObserveCacheGet(metricsDriver, key, metrics.CacheError)
+		return err
+	}
+	if entry == nil {
+		metrics.ObserveCacheGet(metricsDriver, key, metrics.CacheMiss)
+		return fmt.Errorf("%w: %s", ErrCacheMiss, key)
+	}
+	metrics.ObserveCacheGet(metricsDriver, key, metrics.CacheHit)
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("反序列化数据失败: %w", err)
+	}
+	return nil
+}
+
+// Delete 删除缓存
+func (m *MemoryCache) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if elem, ok := m.items[key]; ok {
+		m.removeElement(elem)
+	}
+	return nil
+}
+
+// Exists 检查键是否存在
+func (m *MemoryCache) Exists(ctx context.Context, key string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.get(key) != nil, nil
+}
+
+// SetExpire 设置键的过期时间，键不存在时不做任何事（与Redis一致）
+func (m *MemoryCache) SetExpire(ctx context.Context, key string, expiration time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := m.get(key)
+	if entry == nil {
+		return nil
+	}
+	if expiration <= 0 {
+		m.removeElement(m.items[key])
+		return nil
+	}
+	entry.expiresAt = m.now().Add(expiration)
+	return nil
+}
+
+// GetTTL 获取键的剩余过期时间，返回值含义见 TTLNoExpire、TTLNotFound
+func (m *MemoryCache) GetTTL(ctx context.Context, key string) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := m.get(key)
+	if entry == nil {
+		return TTLNotFound, nil
+	}
+	if entry.expiresAt.IsZero() {
+		return TTLNoExpire, nil
+	}
+	return entry.expiresAt.Sub(m.now()), nil
+}
+
+// IncrBy 增加数值，键不存在时从0开始（保留原有的过期时间）
+func (m *MemoryCache) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, err := m.lookup(key, kindString)
+	if err != nil {
+		return 0, err
+	}
+
+	var current int64
+	if entry != nil {
+		current, err = strconv.ParseInt(string(entry.str), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("增加数值失败: 值不是整数: %s", key)
+		}
+	} else {
+		entry = &memoryEntry{key: key, kind: kindString}
+		m.put(entry)
+	}
+
+	current += value
+	entry.str = []byte(strconv.FormatInt(current, 10))
+	return current, nil
+}
+
+// DecrBy 减少数值
+func (m *MemoryCache) DecrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return m.IncrBy(ctx, key, -value)
+}
+
+// HSet 设置哈希字段
+func (m *MemoryCache) HSet(ctx context.Context, key, field string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("序列化哈希值失败: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, err := m.lookup(key, kindHash)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		entry = &memoryEntry{key: key, kind: kindHash, hash: make(map[string][]byte)}
+		m.put(entry)
+	}
+	entry.hash[field] = data
+	return nil
+}
+
+// HGet 获取哈希字段
+func (m *MemoryCache) HGet(ctx context.Context, key, field string, dest interface{}) error {
+	m.mu.Lock()
+	entry, err := m.lookup(key, kindHash)
+	var data []byte
+	var found bool
+	if entry != nil {
+		data, found = entry.hash[field]
+	}
+	m.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%w: %s.%s", ErrCacheMiss, key, field)
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("反序列化哈希值失败: %w", err)
+	}
+	return nil
+}
+
+// HDel 删除哈希字段，字段全部删除后键也随之删除（与Redis一致）
+func (m *MemoryCache) HDel(ctx context.Context, key string, fields ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, err := m.lookup(key, kindHash)
+	if err != nil || entry == nil {
+		return err
+	}
+	for _, field := range fields {
+		delete(entry.hash, field)
+	}
+	if len(entry.hash) == 0 {
+		m.removeElement(m.items[key])
+	}
+	return nil
+}
+
+// HGetAll 获取哈希的所有字段，键不存在时返回空map
+func (m *MemoryCache) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, err := m.lookup(key, kindHash)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string)
+	if entry != nil {
+		for field, data := range entry.hash {
+			result[field] = string(data)
+		}
+	}
+	return result, nil
+}
+
+// LPush 从列表左侧推入元素
+func (m *MemoryCache) LPush(ctx context.Context, key string, values ...interface{}) error {
+	serialized := make([][]byte, len(values))
+	for i, v := range values {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("序列化列表元素失败: %w", err)
+		}
+		serialized[i] = data
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, err := m.lookup(key, kindList)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		entry = &memoryEntry{key: key, kind: kindList}
+		m.put(entry)
+	}
+	// 与Redis一致：LPUSH a b c 之后列表为 c b a
+	for _, data := range serialized {
+		entry.list = append([][]byte{data}, entry.list...)
+	}
+	return nil
+}
+
+// RPop 从列表右侧弹出元素
+func (m *MemoryCache) RPop(ctx context.Context, key string, dest interface{}) error {
+	m.mu.Lock()
+	entry, err := m.lookup(key, kindList)
+	var data []byte
+	if entry != nil && len(entry.list) > 0 {
+		last := len(entry.list) - 1
+		data = entry.list[last]
+		entry.list = entry.list[:last]
+		if len(entry.list) == 0 {
+			m.removeElement(m.items[key])
+		}
+	}
+	m.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		return fmt.Errorf("%w: %s", ErrCacheMiss, key)
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("反序列化列表元素失败: %w", err)
+	}
+	return nil
+}
+
+// LLen 获取列表长度
+func (m *MemoryCache) LLen(ctx context.Context, key string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, err := m.lookup(key, kindList)
+	if err != nil || entry == nil {
+		return 0, err
+	}
+	return int64(len(entry.list)), nil
+}
+
+// Ping 进程内缓存始终可用
+func (m *MemoryCache) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close 清空缓存
+func (m *MemoryCache) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ll.Init()
+	m.items = make(map[string]*list.Element)
+	return nil
+}
+
+// Len 返回当前保存的键数量（包括尚未被惰性删除的过期键）
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.ll.Len()
+}
+
+// get 获取未过期的条目并标记为最近使用，调用方需持有锁
+func (m *MemoryCache) get(key string) *memoryEntry {
+	elem, ok := m.items[key]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*memoryEntry)
+	if entry.expired(m.now()) {
+		m.removeElement(elem)
+		return nil
+	}
+	m.ll.MoveToFront(elem)
+	return entry
+}
+
+// lookup 获取条目并校验类型，调用方需持有锁
+func (m *MemoryCache) lookup(key string, kind int) (*memoryEntry, error) {
+	entry := m.get(key)
+	if entry != nil && entry.kind != kind {
+		return nil, fmt.Errorf("键的类型不匹配: %s", key)
+	}
+	return entry, nil
+}
+
+// put 写入条目，超出容量时淘汰最久未使用的键，调用方需持有锁
+func (m *MemoryCache) put(entry *memoryEntry) {
+	if elem, ok := m.items[entry.key]; ok {
+		elem.Value = entry
+		m.ll.MoveToFront(elem)
+		return
+	}
+
+	m.items[entry.key] = m.ll.PushFront(entry)
+	for m.ll.Len() > m.maxEntries {
+		m.removeElement(m.ll.Back())
+	}
+}
+
+// removeElement 删除链表节点和索引，调用方需持有锁
+func (m *MemoryCache) removeElement(elem *list.Element) {
+	m.ll.Remove(elem)
+	delete(m.items, elem.Value.(*memoryEntry).key)
+}
@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMemoryCache 创建使用可控时钟的内存缓存
+func newTestMemoryCache(maxEntries int) (*MemoryCache, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewMemoryCache(maxEntries)
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+func TestMemoryCache_SetGet(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestMemoryCache(10)
+
+	type user struct {
+		ID   uint   `json:"id"`
+		Name string `json:"name"`
+	}
+	require.NoError(t, m.Set(ctx, "user:1", user{ID: 1, Name: "alice"}, 0))
+
+	var got user
+	require.NoError(t, m.Get(ctx, "user:1", &got))
+	assert.Equal(t, user{ID: 1, Name: "alice"}, got)
+
+	err := m.Get(ctx, "user:2", &got)
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestMemoryCache_TTL(t *testing.T) {
+	ctx := context.Background()
+	m, now := newTestMemoryCache(10)
+
+	require.NoError(t, m.Set(ctx, "k", "v", time.Minute))
+	ttl, err := m.GetTTL(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, ttl)
+
+	*now = now.Add(time.Minute)
+	exists, err := m.Exists(ctx, "k")
+	require.NoError(t, err)
+	assert.False(t, exists, "过期后键应当不存在")
+
+	ttl, err = m.GetTTL(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, TTLNotFound, ttl)
+
+	require.NoError(t, m.Set(ctx, "forever", "v", 0))
+	ttl, err = m.GetTTL(ctx, "forever")
+	require.NoError(t, err)
+	assert.Equal(t, TTLNoExpire, ttl)
+}
+
+func TestMemoryCache_LRUEviction(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestMemoryCache(2)
+
+	require.NoError(t, m.Set(ctx, "a", 1, 0))
+	require.NoError(t, m.Set(ctx, "b", 2, 0))
+
+	// 访问 a 使其成为最近使用，再写入 c 时应淘汰 b
+	var v int
+	require.NoError(t, m.Get(ctx, "a", &v))
+	require.NoError(t, m.Set(ctx, "c", 3, 0))
+
+	assert.Equal(t, 2, m.Len())
+	assert.NoError(t, m.Get(ctx, "a", &v))
+	assert.ErrorIs(t, m.Get(ctx, "b", &v), ErrCacheMiss)
+	assert.NoError(t, m.Get(ctx, "c", &v))
+}
+
+func TestMemoryCache_IncrBy(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestMemoryCache(10)
+
+	n, err := m.IncrBy(ctx, "counter", 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	n, err = m.DecrBy(ctx, "counter", 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-3), n)
+
+	// Set 写入的整数同样可以累加（与Redis一致）
+	require.NoError(t, m.Set(ctx, "set-counter", 10, 0))
+	n, err = m.IncrBy(ctx, "set-counter", 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), n)
+
+	require.NoError(t, m.Set(ctx, "text", "abc", 0))
+	_, err = m.IncrBy(ctx, "text", 1)
+	assert.Error(t, err)
+}
+
+func TestMemoryCache_Hash(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestMemoryCache(10)
+
+	require.NoError(t, m.HSet(ctx, "h", "a", 1))
+	require.NoError(t, m.HSet(ctx, "h", "b", "x"))
+
+	var a int
+	require.NoError(t, m.HGet(ctx, "h", "a", &a))
+	assert.Equal(t, 1, a)
+
+	all, err := m.HGetAll(ctx, "h")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1", "b": `"x"`}, all)
+
+	require.NoError(t, m.HDel(ctx, "h", "a", "b"))
+	exists, err := m.Exists(ctx, "h")
+	require.NoError(t, err)
+	assert.False(t, exists, "字段全部删除后键也应删除")
+
+	// 类型不匹配时报错
+	require.NoError(t, m.Set(ctx, "s", "v", 0))
+	assert.Error(t, m.HSet(ctx, "s", "f", 1))
+}
+
+func TestMemoryCache_List(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestMemoryCache(10)
+
+	require.NoError(t, m.LPush(ctx, "q", "a", "b"))
+	require.NoError(t, m.LPush(ctx, "q", "c"))
+
+	n, err := m.LLen(ctx, "q")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	// LPUSH + RPOP 组成先进先出队列
+	for _, want := range []string{"a", "b", "c"} {
+		var got string
+		require.NoError(t, m.RPop(ctx, "q", &got))
+		assert.Equal(t, want, got)
+	}
+
+	var got string
+	assert.ErrorIs(t, m.RPop(ctx, "q", &got), ErrCacheMiss)
+}
@@ -0,0 +1,151 @@
+// Package logger 结构化日志
+// 学习要点：基于标准库 log/slog 的分级结构化日志，文件按大小和天数滚动，
+// 通过 context 传递请求ID，同一请求的所有日志都能按 request_id 串联起来
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"task-management-system/internal/config"
+)
+
+// 日志输出格式
+const (
+	FormatJSON    = "json"    // 每行一个JSON对象，便于日志系统采集
+	FormatConsole = "console" // key=value 文本，便于本地阅读
+)
+
+// defaultMaxSize 单个日志文件默认最大大小(MB)
+const defaultMaxSize = 100
+
+// requestIDKey 请求ID在 context 中的键
+type requestIDKey struct{}
+
+// globalLevel 全局日志记录器的级别，支持运行时修改
+var globalLevel slog.LevelVar
+
+// New 根据日志配置创建日志记录器
+// 学习要点：始终输出到标准输出；配置了文件路径时同时写入滚动文件
+func New(cfg *config.LogConfig) (*slog.Logger, error) {
+	l, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	return newLogger(cfg, l)
+}
+
+// newLogger 按指定级别创建日志记录器
+func newLogger(cfg *config.LogConfig, leveler slog.Leveler) (*slog.Logger, error) {
+	var output io.Writer = os.Stdout
+	if cfg.FilePath != "" {
+		maxSize := cfg.MaxSize
+		if maxSize <= 0 {
+			maxSize = defaultMaxSize
+		}
+		output = io.MultiWriter(os.Stdout, &lumberjack.Logger{
+			Filename:   cfg.FilePath,
+			MaxSize:    maxSize,        // 单个文件最大大小(MB)，超过后滚动
+			MaxBackups: cfg.MaxBackups, // 保留的旧文件数量，0 表示不限
+			MaxAge:     cfg.MaxAge,     // 旧文件保留天数，0 表示不限
+			LocalTime:  true,
+		})
+	}
+
+	opts := &slog.HandlerOptions{Level: leveler}
+	var handler slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "", FormatJSON:
+		handler = slog.NewJSONHandler(output, opts)
+	case FormatConsole:
+		handler = slog.NewTextHandler(output, opts)
+	default:
+		return nil, fmt.Errorf("无效的日志格式: %s", cfg.Format)
+	}
+
+	return slog.New(&contextHandler{Handler: handler}), nil
+}
+
+// Init 创建日志记录器并设置为全局默认
+// 学习要点：设置后 slog.InfoContext 等包级函数都会使用这里的配置
+func Init(cfg *config.LogConfig) error {
+	if err := SetLevel(cfg.Level); err != nil {
+		return err
+	}
+	log, err := newLogger(cfg, &globalLevel)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(log)
+	return nil
+}
+
+// SetLevel 修改全局日志记录器的级别
+// 学习要点：slog.LevelVar 可以并发安全地修改，配置热更新时无需重建日志记录器
+func SetLevel(s string) error {
+	l, err := ParseLevel(s)
+	if err != nil {
+		return err
+	}
+	globalLevel.Set(l)
+	return nil
+}
+
+// ParseLevel 解析日志级别：debug、info、warn、error，空值为 info
+func ParseLevel(level string) (slog.Level, error) {
+	if level == "" {
+		return slog.LevelInfo, nil
+	}
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return 0, fmt.Errorf("无效的日志级别: %s", level)
+	}
+	return l, nil
+}
+
+// WithRequestID 把请求ID写入 context
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext 从 context 中读取请求ID
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// contextHandler 从 context 中取出请求ID和链路追踪ID附加到每条日志
+// 学习要点：装饰器模式，包装 slog.Handler 而不改变底层的输出格式
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle 输出日志前追加 request_id 和 trace_id 字段
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		record.AddAttrs(slog.String("trace_id", spanContext.TraceID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs 保持包装，避免 logger.With 之后丢失 request_id
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup 保持包装，避免 logger.WithGroup 之后丢失 request_id
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,110 @@
+// Package metrics Prometheus 指标
+// 学习要点：指标集中定义并注册到独立的 Registry，HTTP、数据库和缓存各自只负责上报，
+// /metrics 端点输出 Registry 中的全部指标
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// 缓存读取结果
+const (
+	CacheHit   = "hit"   // 命中
+	CacheMiss  = "miss"  // 未命中
+	CacheError = "error" // 缓存故障
+)
+
+// Registry 应用指标注册表
+// 学习要点：不使用 prometheus.DefaultRegisterer，避免第三方库的指标混入，测试时也互不干扰
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequestsTotal HTTP请求总数
+	HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP请求总数",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration HTTP请求耗时
+	// 学习要点：route 使用路由模板（如 /api/v1/tasks/:id）而不是实际路径，避免标签基数爆炸
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP请求耗时（秒）",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestsInFlight 正在处理的HTTP请求数
+	HTTPRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "正在处理的HTTP请求数",
+	})
+
+	// DBQueryDuration 数据库语句耗时
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "数据库语句耗时（秒）",
+		Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"operation", "table", "status"})
+
+	// CacheRequestsTotal 缓存读取次数
+	// 学习要点：命中率 = hit / (hit + miss)，用来评估 cache-aside 策略的效果
+	CacheRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_requests_total",
+		Help: "缓存读取次数",
+	}, []string{"driver", "prefix", "result"})
+
+	// RateLimitRejectedTotal 被限流拒绝的请求数
+	RateLimitRejectedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rate_limit_rejected_total",
+		Help: "被限流拒绝的请求数",
+	}, []string{"group"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestsTotal,
+		HTTPRequestDuration,
+		HTTPRequestsInFlight,
+		DBQueryDuration,
+		CacheRequestsTotal,
+		RateLimitRejectedTotal,
+	)
+}
+
+// Handler 返回 /metrics 端点的处理器
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDBStats 注册 sql.DB 连接池指标（打开、使用中、空闲连接数和等待次数等）
+// 学习要点：同名数据库重复注册时忽略，多次调用是安全的
+func RegisterDBStats(db *sql.DB, dbName string) error {
+	err := Registry.Register(collectors.NewDBStatsCollector(db, dbName))
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		return nil
+	}
+	return err
+}
+
+// ObserveCacheGet 记录一次缓存读取的结果
+func ObserveCacheGet(driver, key, result string) {
+	CacheRequestsTotal.WithLabelValues(driver, keyPrefix(key), result).Inc()
+}
+
+// keyPrefix 取缓存键的前缀（如 user:1 → user），作为标签值
+func keyPrefix(key string) string {
+	if prefix, _, found := strings.Cut(key, ":"); found {
+		return prefix
+	}
+	return "other"
+}
@@ -0,0 +1,123 @@
+// Package ratelimit 滑动窗口限流
+// 学习要点：基于 cache.Cache 的计数器实现，使用 Redis 时多个实例共享限额；
+// Redis 故障时退化为进程内计数，限流仍然生效，只是限额变为每个实例独立计算
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"task-management-system/pkg/cache"
+)
+
+// Rule 限流规则：每个窗口内最多 Requests 个请求
+type Rule struct {
+	Requests int           // 窗口内最多请求数
+	Window   time.Duration // 窗口长度
+}
+
+// Result 限流判断结果，用于设置 X-RateLimit-* 响应头
+type Result struct {
+	Allowed    bool          // 是否放行
+	Limit      int           // 窗口内最多请求数
+	Remaining  int           // 当前窗口剩余请求数
+	Reset      time.Duration // 距当前窗口结束的时间
+	RetryAfter time.Duration // 被拒绝时建议的重试等待时间
+}
+
+// Limiter 滑动窗口限流器
+// 学习要点：滑动窗口计数法只保存当前和上一个固定窗口的计数，
+// 估算值 = 上一窗口计数 × 上一窗口在滑动窗口中的剩余比例 + 当前窗口计数，
+// 既避免了固定窗口在边界处放行两倍请求的问题，又不需要记录每个请求的时间戳
+type Limiter struct {
+	store    cache.Cache
+	fallback cache.Cache
+	degraded atomic.Bool
+	now      func() time.Time
+}
+
+// New 创建限流器；fallback 为 nil 时 store 故障直接返回错误
+func New(store, fallback cache.Cache) *Limiter {
+	return &Limiter{store: store, fallback: fallback, now: time.Now}
+}
+
+// Allow 判断 key 对应的请求是否放行
+func (l *Limiter) Allow(ctx context.Context, key string, rule Rule) (*Result, error) {
+	result, err := l.allow(ctx, l.store, key, rule)
+	if err == nil {
+		if l.degraded.CompareAndSwap(true, false) {
+			slog.InfoContext(ctx, "限流存储已恢复")
+		}
+		return result, nil
+	}
+	if l.fallback == nil {
+		return nil, err
+	}
+
+	// 只在首次降级时记录日志，避免故障期间每个请求都打一条
+	if l.degraded.CompareAndSwap(false, true) {
+		slog.WarnContext(ctx, "限流存储不可用，改用进程内计数", "error", err)
+	}
+	return l.allow(ctx, l.fallback, key, rule)
+}
+
+// allow 使用指定存储执行滑动窗口计数
+func (l *Limiter) allow(ctx context.Context, store cache.Cache, key string, rule Rule) (*Result, error) {
+	now := l.now()
+	window := rule.Window
+	index := now.UnixNano() / int64(window)
+	elapsed := time.Duration(now.UnixNano() - index*int64(window))
+	weight := 1 - float64(elapsed)/float64(window) // 上一窗口仍在滑动窗口中的比例
+
+	currentKey := fmt.Sprintf("%s:%d", key, index)
+	current, err := store.IncrBy(ctx, currentKey, 1)
+	if err != nil {
+		return nil, err
+	}
+	if current == 1 {
+		// 上一窗口的计数在下一个窗口中还要使用，因此保留两个窗口长度
+		if err := store.SetExpire(ctx, currentKey, 2*window); err != nil {
+			return nil, err
+		}
+	}
+
+	var previous int64
+	if err := store.Get(ctx, fmt.Sprintf("%s:%d", key, index-1), &previous); err != nil && !errors.Is(err, cache.ErrCacheMiss) {
+		return nil, err
+	}
+
+	estimated := float64(previous)*weight + float64(current)
+	result := &Result{
+		Allowed:   estimated <= float64(rule.Requests),
+		Limit:     rule.Requests,
+		Remaining: max(0, rule.Requests-int(math.Ceil(estimated))),
+		Reset:     window - elapsed,
+	}
+	if result.Allowed {
+		return result, nil
+	}
+
+	// 被拒绝的请求不计入限额，客户端停止请求后能按预期恢复
+	if _, err := store.DecrBy(ctx, currentKey, 1); err != nil {
+		return nil, err
+	}
+	result.RetryAfter = retryAfter(previous, current, rule.Requests, weight, window, elapsed)
+	return result, nil
+}
+
+// retryAfter 估算上一窗口的权重衰减到足以放行下一个请求所需的时间；
+// 当前窗口本身已经超限时只能等到窗口结束
+func retryAfter(previous, current int64, limit int, weight float64, window, elapsed time.Duration) time.Duration {
+	spare := float64(limit) - float64(current)
+	if spare < 0 || previous == 0 {
+		return window - elapsed
+	}
+	// 需要满足 previous × w' + current ≤ limit，即 w' ≤ spare / previous
+	target := spare / float64(previous)
+	return time.Duration((weight - target) * float64(window))
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"task-management-system/pkg/cache"
+)
+
+// failingCache 模拟不可用的 Redis
+type failingCache struct {
+	cache.Cache
+}
+
+func (failingCache) IncrBy(ctx context.Context, key string, value int64) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func newTestLimiter(store, fallback cache.Cache, now *time.Time) *Limiter {
+	l := New(store, fallback)
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	rule := Rule{Requests: 3, Window: time.Minute}
+	now := time.Unix(1_700_000_040, 0).Truncate(time.Minute) // 从窗口起点开始
+	limiter := newTestLimiter(cache.NewMemoryCache(0), nil, &now)
+
+	for i := 0; i < 3; i++ {
+		result, err := limiter.Allow(ctx, "k", rule)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2-i, result.Remaining)
+	}
+
+	result, err := limiter.Allow(ctx, "k", rule)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed, "超过限额")
+	assert.Equal(t, 0, result.Remaining)
+	assert.Equal(t, time.Minute, result.RetryAfter, "当前窗口已满，需等到窗口结束")
+
+	other, err := limiter.Allow(ctx, "other", rule)
+	require.NoError(t, err)
+	assert.True(t, other.Allowed, "不同的键分别计数")
+
+	// 下一个窗口过去 1/3：上一窗口的3个请求按 2/3 计入，估算值为 2，还能再放行1个
+	now = now.Add(time.Minute + 20*time.Second)
+	result, err = limiter.Allow(ctx, "k", rule)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	result, err = limiter.Allow(ctx, "k", rule)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed, "滑动窗口仍包含上一窗口的大部分请求")
+	// 需要上一窗口权重降到 (3-2)/3，即再过 20 秒
+	assert.Equal(t, 20*time.Second, result.RetryAfter)
+	assert.Equal(t, 40*time.Second, result.Reset)
+
+	// 被拒绝的请求不计数：20 秒后可以再放行
+	now = now.Add(20 * time.Second)
+	result, err = limiter.Allow(ctx, "k", rule)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+}
+
+func TestLimiter_Fallback(t *testing.T) {
+	ctx := context.Background()
+	rule := Rule{Requests: 1, Window: time.Minute}
+	now := time.Unix(1_700_000_000, 0)
+
+	withoutFallback := newTestLimiter(failingCache{}, nil, &now)
+	_, err := withoutFallback.Allow(ctx, "k", rule)
+	assert.Error(t, err)
+
+	limiter := newTestLimiter(failingCache{}, cache.NewMemoryCache(0), &now)
+	result, err := limiter.Allow(ctx, "k", rule)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	result, err = limiter.Allow(ctx, "k", rule)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed, "降级后仍然限流")
+}
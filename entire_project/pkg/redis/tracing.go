@@ -0,0 +1,71 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"task-management-system/pkg/tracing"
+)
+
+// tracingHook 为每个 Redis 命令创建 Span 的钩子
+// 学习要点：go-redis 的 Hook 以中间件链的方式包装命令执行，
+// 只记录命令名而不记录参数，避免把缓存内容写进链路数据
+type tracingHook struct{}
+
+// 编译期检查 tracingHook 实现了 redis.Hook 接口
+var _ redis.Hook = tracingHook{}
+
+// DialHook 建立连接时不创建 Span
+func (tracingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook 单个命令
+func (tracingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := startSpan(ctx, "redis."+cmd.Name(), attribute.String("db.operation", cmd.Name()))
+		defer span.End()
+
+		err := next(ctx, cmd)
+		recordError(span, err)
+		return err
+	}
+}
+
+// ProcessPipelineHook 管道命令，一个管道一个 Span
+func (tracingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := startSpan(ctx, "redis.pipeline", attribute.Int("db.redis.pipeline_length", len(cmds)))
+		defer span.End()
+
+		err := next(ctx, cmds)
+		recordError(span, err)
+		return err
+	}
+}
+
+// startSpan 创建 Redis 客户端 Span
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, semconv.DBSystemRedis)
+	return tracing.Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// recordError 记录命令错误，缓存未命中（redis.Nil）不算错误
+func recordError(span trace.Span, err error) {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
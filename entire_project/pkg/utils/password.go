@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HashPassword 使用 bcrypt 加密密码
+// 学习要点：bcrypt 自带随机盐值，同一个密码每次加密结果都不同
+func HashPassword(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("密码加密失败: %w", err)
+	}
+	return string(hashed), nil
+}
+
+// CheckPassword 校验明文密码与 bcrypt 哈希是否匹配
+func CheckPassword(hashed, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(password)) == nil
+}
+
+// IsBcryptHash 判断字符串是否已经是 bcrypt 哈希
+// 学习要点：bcrypt 哈希固定为60个字符，以 $2a$/$2b$/$2y$ 开头
+func IsBcryptHash(s string) bool {
+	if len(s) != 60 {
+		return false
+	}
+	return strings.HasPrefix(s, "$2a$") || strings.HasPrefix(s, "$2b$") || strings.HasPrefix(s, "$2y$")
+}
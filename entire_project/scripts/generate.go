@@ -14,12 +14,13 @@ import (
 
 func main() {
 	// 加载配置
-	if err := config.Load("../configs/config.yaml"); err != nil {
+	appConfig, err := config.Load("../configs/config.yaml")
+	if err != nil {
 		log.Fatalf("加载配置失败: %v", err)
 	}
 
 	// 连接数据库
-	cfg := &config.GlobalConfig.Database.MySQL
+	cfg := &appConfig.Database.MySQL
 	dsn := cfg.GetMySQLDSN()
 	
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
@@ -143,7 +143,7 @@ fi
 # 7. 构建项目
 print_message "构建项目..."
 
-go build -o bin/server cmd/server/main.go
+go build -o bin/server ./cmd/server
 if [ $? -eq 0 ]; then
     print_message "项目构建成功 ✅"
 else
@@ -197,7 +197,7 @@ fi
 # 检查二进制文件
 if [ ! -f "bin/server" ]; then
     echo "📦 构建项目..."
-    go build -o bin/server cmd/server/main.go
+    go build -o bin/server ./cmd/server
 fi
 
 # 启动服务器
@@ -236,7 +236,7 @@ cat > dev.sh << 'EOF'
 case "$1" in
     "build")
         echo "📦 构建项目..."
-        go build -o bin/server cmd/server/main.go
+        go build -o bin/server ./cmd/server
         ;;
     "test")
         echo "🧪 运行测试..."